@@ -0,0 +1,116 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "math"
+)
+
+// ===== Angles and cusp proximity =====
+
+type AngleContact struct {
+    Angle   string  `json:"angle"` // ascendant, descendant, midheaven or imum_coeli
+    Orb     float64 `json:"orb"`   // degrees, either side
+    Meaning string  `json:"text"`
+}
+
+// CuspInfluence is set when Chiron sits in the last degrees of a house. The
+// next house's texts are returned so the two readings can be blended.
+type CuspInfluence struct {
+    House            int     `json:"house"`    // the house whose cusp Chiron is approaching
+    Distance         float64 `json:"distance"` // degrees before the cusp
+    Note             string  `json:"note"`
+    TraditionalWound string  `json:"traditional_wound"`
+    LHPStrength      string  `json:"lhp_strength"`
+}
+
+var angleTexts = map[string]string{
+    "ascendant":  "Chiron on the Ascendant is worn on the surface: the wound shapes first impressions, the body and the way you meet the world, and others often sense it before you name it.",
+    "descendant": "Chiron on the Descendant meets the wound through others: partners and open rivals mirror it back, and relationships become the main place of both hurt and healing.",
+    "midheaven":  "Chiron on the Midheaven makes the wound public: vocation and reputation are bound up with it, and many with this placement end up healing professionally.",
+    "imum_coeli": "Chiron on the IC roots the wound in home and family: it sits in the foundations, and healing comes through making peace with where you come from.",
+}
+
+// computeAngles returns the Ascendant and Midheaven. Whole-sign houses are
+// requested because the angles don't depend on the house system and this one
+// never fails at polar latitudes.
+func computeAngles(jd, lat, lon float64, opts calcOptions) (asc, mc float64, err error) {
+    cusps := make([]float64, 13)
+    ascmc := make([]float64, 10)
+    withEphemeris(opts, func() {
+        err = eph.HousesEx(jd, opts.houseFlags(), lat, lon, 'W', cusps, ascmc)
+    })
+    if err != nil {
+        ephemerisErrors.inc("houses")
+        return 0, 0, errors.New("angle calculation failed")
+    }
+    return ascmc[0], ascmc[1], nil
+}
+
+// wholeSignCusps returns cusps[1..12] at the sign boundaries, in the same
+// shape as computeHouseCusps.
+func wholeSignCusps(ascSignIndex int) []float64 {
+    cusps := make([]float64, 13)
+    for i := 1; i <= 12; i++ {
+        cusps[i] = float64((ascSignIndex+i-1)%12) * 30
+    }
+    return cusps
+}
+
+// angleDistance is the shortest arc between two longitudes.
+func angleDistance(a, b float64) float64 {
+    d := math.Mod(math.Abs(a-b), 360)
+    return math.Min(d, 360-d)
+}
+
+// findAngleContacts lists the angles within orb of lon.
+func findAngleContacts(lon, asc, mc, orb float64) []AngleContact {
+    angles := []struct {
+        name string
+        at   float64
+    }{
+        {"ascendant", asc},
+        {"descendant", math.Mod(asc+180, 360)},
+        {"midheaven", mc},
+        {"imum_coeli", math.Mod(mc+180, 360)},
+    }
+    var contacts []AngleContact
+    for _, a := range angles {
+        if d := angleDistance(lon, a.at); d <= orb {
+            contacts = append(contacts, AngleContact{Angle: a.name, Orb: math.Round(d*100) / 100, Meaning: angleTexts[a.name]})
+        }
+    }
+    return contacts
+}
+
+// findCuspInfluence reports the next house when lon is within orb of its cusp.
+func findCuspInfluence(lon float64, house int, cusps []float64, sign string, orb float64) *CuspInfluence {
+    next := house%12 + 1
+    d := math.Mod(cusps[next]-lon+360, 360)
+    if d > orb {
+        return nil
+    }
+    wound, strength := getInterpretation(sign, next)
+    return &CuspInfluence{
+        House:    next,
+        Distance: math.Round(d*100) / 100,
+        Note: fmt.Sprintf("Chiron is %.1f° from the %s house cusp. Read the %s house interpretation together with this one: "+
+            "its themes are already colouring the placement.", d, ordinal(next), ordinal(next)),
+        TraditionalWound: wound,
+        LHPStrength:      strength,
+    }
+}
+
+func ordinal(n int) string {
+    suffix := "th"
+    switch {
+    case n%100 >= 11 && n%100 <= 13:
+    case n%10 == 1:
+        suffix = "st"
+    case n%10 == 2:
+        suffix = "nd"
+    case n%10 == 3:
+        suffix = "rd"
+    }
+    return fmt.Sprintf("%d%s", n, suffix)
+}
@@ -0,0 +1,260 @@
+// Code generated by go run ./tools/approxfit; DO NOT EDIT.
+
+package main
+
+// keplerElements are JPL's approximate Keplerian elements (Standish), J2000
+// ecliptic: a (AU), e, I, L, long. perihelion, long. node (degrees), each
+// with its rate per century.
+var keplerElements = map[int][12]float64{
+    SE_MERCURY: {0.38709927, 0.20563593, 7.00497902, 252.25032350, 77.45779628, 48.33076593,
+        0.00000037, 0.00001906, -0.00594749, 149472.67411175, 0.16047689, -0.12534081},
+    SE_VENUS: {0.72333566, 0.00677672, 3.39467605, 181.97909950, 131.60246718, 76.67984255,
+        0.00000390, -0.00004107, -0.00078890, 58517.81538729, 0.00268329, -0.27769418},
+    earthBary: {1.00000261, 0.01671123, -0.00001531, 100.46457166, 102.93768193, 0.00000000,
+        0.00000562, -0.00004392, -0.01294668, 35999.37244981, 0.32327364, 0.00000000},
+    SE_MARS: {1.52371034, 0.09339410, 1.84969142, -4.55343205, -23.94362959, 49.55953891,
+        0.00001847, 0.00007882, -0.00813131, 19140.30268499, 0.44441088, -0.29257343},
+    SE_JUPITER: {5.20288700, 0.04838624, 1.30439695, 34.39644051, 14.72847983, 100.47390909,
+        -0.00011607, -0.00013253, -0.00183714, 3034.74612775, 0.21252668, 0.20469106},
+    SE_SATURN: {9.53667594, 0.05386179, 2.48599187, 49.95424423, 92.59887831, 113.66242448,
+        -0.00125060, -0.00050991, 0.00193609, 1222.49362201, -0.41897216, -0.28867794},
+    SE_URANUS: {19.18916464, 0.04725744, 0.77263783, 313.23810451, 170.95427630, 74.01692503,
+        -0.00196176, -0.00004397, -0.00242939, 428.48202785, 0.40805281, 0.04240589},
+    SE_NEPTUNE: {30.06992276, 0.00859048, 1.77004347, -55.12002969, 44.96476227, 131.78422574,
+        0.00026291, 0.00005105, 0.00035372, 218.45945325, -0.32241464, -0.00508664},
+    SE_PLUTO: {39.48211675, 0.24882730, 17.14001206, 238.92903833, 224.06891629, 110.30393684,
+        -0.00031596, 0.00005170, 0.00004818, 145.20780515, -0.04062942, -0.01183482},
+}
+
+// approxAyanamsas fit each Swiss Ephemeris ayanamsa over 1800-2200: a
+// quadratic in centuries from J2000, a multiple of the nutation, and the
+// cosine and sine of the Sun's mean longitude for a star's aberration.
+var approxAyanamsas = map[int][6]float64{
+    0: {24.7403000, 1.3968880, 0.0003071, 1.0000, -0.0000000, 0.0000000},
+    1: {23.8570924, 1.3968880, 0.0003071, 1.0000, -0.0000000, 0.0000000},
+    2: {27.8157528, 1.3968784, 0.0003059, 1.0000, -0.0000000, 0.0000000},
+    3: {22.4107910, 1.3968881, 0.0003071, 1.0000, -0.0000000, 0.0000000},
+    4: {20.0575410, 1.3968881, 0.0003071, 1.0000, -0.0000000, 0.0000000},
+    5: {23.7602400, 1.3968881, 0.0003071, 1.0000, -0.0000000, 0.0000000},
+    6: {28.3596786, 1.3968881, 0.0003071, 1.0000, -0.0000000, 0.0000000},
+    7: {22.4788030, 1.3968881, 0.0003071, 1.0000, -0.0000000, 0.0000000},
+    8: {22.7621370, 1.3968881, 0.0003071, 1.0000, -0.0000000, 0.0000000},
+    9: {23.5336399, 1.3968771, 0.0003058, 1.0000, -0.0000000, 0.0000000},
+    10: {24.9336399, 1.3968771, 0.0003058, 1.0000, -0.0000000, 0.0000000},
+    11: {25.7836399, 1.3968771, 0.0003058, 1.0000, -0.0000000, 0.0000000},
+    12: {24.7336399, 1.3968771, 0.0003058, 1.0000, -0.0000000, 0.0000000},
+    13: {24.5225280, 1.3968767, 0.0003058, 1.0000, -0.0000000, 0.0000000},
+    14: {24.7589239, 1.3968771, 0.0003058, 1.0000, -0.0000000, 0.0000000},
+    15: {20.2477881, 1.3968767, 0.0003058, 1.0000, -0.0000000, 0.0000000},
+    16: {19.9929594, 1.3968841, 0.0003062, 1.0000, -0.0000000, 0.0000000},
+    17: {26.8517091, 1.3968591, 0.0003128, 0.9997, 0.0003113, 0.0057079},
+    18: {0.0000000, 1.3968879, 0.0003071, 1.0000, -0.0000000, 0.0000000},
+    19: {1.3965810, 1.3968881, 0.0003071, 1.0000, -0.0000000, 0.0000000},
+    20: {0.6983702, 1.3968880, 0.0003071, 1.0000, -0.0000000, 0.0000000},
+    21: {20.8950589, 1.3968836, 0.0003062, 1.0000, -0.0000000, 0.0000000},
+    22: {20.6804249, 1.3968836, 0.0003062, 1.0000, -0.0000000, 0.0000000},
+    23: {20.8950598, 1.3968836, 0.0003062, 1.0000, -0.0000000, 0.0000000},
+    24: {20.6574274, 1.3968836, 0.0003062, 1.0000, -0.0000000, 0.0000000},
+    25: {20.1033884, 1.3968836, 0.0003062, 1.0000, -0.0000000, 0.0000000},
+    26: {23.0057633, 1.3968836, 0.0003062, 1.0000, -0.0000000, 0.0000000},
+    27: {23.8413593, 1.3957010, 0.0003073, 1.0012, 0.0052077, 0.0023005},
+    28: {20.0442160, 1.4000783, 0.0003068, 0.9987, -0.0053509, -0.0019322},
+    29: {22.7219918, 1.3980941, 0.0003064, 1.0012, 0.0035568, -0.0044419},
+    30: {22.4747681, 1.3968591, 0.0003128, 0.9997, 0.0003113, 0.0057079},
+    31: {30.0231724, 1.4043378, 0.0002987, 1.0000, 0.0000000, -0.0000001},
+    32: {30.0760921, 1.4043356, 0.0002986, 1.0000, 0.0000000, -0.0000001},
+    33: {23.4094255, 1.4043356, 0.0002986, 1.0000, 0.0000000, -0.0000001},
+    34: {30.0177939, 1.3968879, 0.0003071, 1.0000, -0.0000000, 0.0000000},
+    35: {24.5857125, 1.3966695, 0.0003208, 0.9998, 0.0005519, 0.0058318},
+    36: {20.0451444, 1.4585869, 0.0004874, 0.9997, 0.0003396, 0.0059602},
+    37: {20.5758470, 1.3968838, 0.0003062, 1.0000, -0.0000000, 0.0000000},
+    38: {24.6157528, 1.3968784, 0.0003059, 1.0000, -0.0000000, 0.0000000},
+    39: {25.2293496, 1.3980941, 0.0003064, 1.0012, 0.0035568, -0.0044419},
+    40: {356.8517091, 1.3968591, 0.0003128, 0.9997, 0.0003113, 0.0057079},
+    41: {25.0000191, 1.3968879, 0.0003071, 1.0000, -0.0000000, 0.0000000},
+    42: {22.7956093, 1.3968801, 0.0003060, 1.0000, -0.0000000, 0.0000000},
+    43: {23.8423233, 1.3968881, 0.0003071, 1.0000, -0.0000000, 0.0000000},
+    44: {23.8634812, 1.3968816, 0.0003061, 1.0000, -0.0000000, 0.0000000},
+    45: {23.7803650, 1.3968816, 0.0003061, 1.0000, -0.0000000, 0.0000000},
+    46: {23.8567890, 1.3968880, 0.0003071, 1.0000, -0.0000000, 0.0000000},
+}
+
+// keplerFits correct the Keplerian position between these TT
+// centuries from J2000 (1800-2200).
+const keplerFitStart, keplerFitEnd = -1.999959, 2.009952
+
+var keplerFits = map[int]keplerFit{
+    SE_MERCURY: {
+        lon: [9]float64{2.06, 3.85, 0.02, -0.10, -0.04, 0.05, -0.00, 0.01, -0.01},
+        lat: [9]float64{0.19, 0.21, -0.01, -0.00, -0.00, -0.00, -0.00, -0.00, -0.00},
+        dist: [9]float64{-1.04, -0.52, -0.02, 0.02, -0.02, 0.01, -0.02, 0.01, -0.01},
+        terms: []keplerTerm{
+            {2, SE_VENUS, -5, [6]float64{1.30, 7.21, -0.17, -0.01, -0.19, 0.02}, [6]float64{0.02, 0.05, 0.01, -0.03, -0.05, -0.02}, [6]float64{0.13, 0.05, -0.14, 0.02, 0.02, 0.19}},
+            {1, 0, 0, [6]float64{-1.58, 3.40, 1.12, 6.31, -0.01, 0.06}, [6]float64{-0.08, 0.27, -0.30, 0.43, -0.04, 0.07}, [6]float64{3.16, 1.66, 5.86, -0.86, 0.05, 0.01}},
+            {1, SE_VENUS, -2, [6]float64{-1.21, -3.51, 0.05, -0.02, -0.00, 0.01}, [6]float64{-0.08, -0.06, 0.00, 0.00, -0.00, 0.00}, [6]float64{-0.63, 0.22, -0.00, -0.01, 0.00, -0.00}},
+            {1, SE_JUPITER, -2, [6]float64{-1.96, 2.66, -0.02, -0.01, -0.05, 0.03}, [6]float64{0.01, 0.19, 0.00, -0.00, -0.01, -0.00}, [6]float64{2.36, 1.74, -0.01, 0.01, 0.03, 0.05}},
+            {3, SE_VENUS, -5, [6]float64{0.41, 2.79, -0.05, 0.02, -0.06, 0.02}, [6]float64{-0.08, 0.42, -0.01, -0.01, -0.02, -0.00}, [6]float64{2.57, -0.37, 0.01, 0.05, 0.01, 0.06}},
+            {2, SE_VENUS, -2, [6]float64{-0.66, -1.99, 0.03, -0.01, -0.01, 0.00}, [6]float64{0.01, -0.19, 0.00, 0.00, -0.00, 0.00}, [6]float64{-1.91, 0.64, -0.01, -0.02, 0.00, 0.00}},
+            {1, SE_VENUS, -5, [6]float64{0.30, 1.44, -0.03, 0.05, 0.02, -0.00}, [6]float64{0.27, 0.33, -0.00, 0.01, 0.00, -0.00}, [6]float64{-1.39, 0.29, -0.05, -0.02, 0.00, 0.02}},
+            {2, SE_VENUS, -3, [6]float64{-1.28, -0.35, 0.01, -0.01, -0.00, 0.00}, [6]float64{-0.00, 0.06, -0.00, 0.00, -0.00, -0.00}, [6]float64{-0.27, 0.96, -0.01, -0.00, 0.00, -0.00}},
+            {2, 0, 0, [6]float64{-0.40, 0.85, 0.31, 1.58, -0.00, 0.01}, [6]float64{-0.20, 0.18, -0.18, 0.43, -0.01, 0.01}, [6]float64{0.64, 0.31, 1.18, -0.19, 0.01, 0.01}},
+            {2, SE_JUPITER, -2, [6]float64{-0.60, 0.71, 0.04, -0.06, -0.10, -0.07}, [6]float64{-0.18, 0.09, 0.04, -0.02, -0.02, -0.05}, [6]float64{0.58, 0.48, -0.05, -0.04, -0.06, 0.07}},
+        },
+    },
+    SE_VENUS: {
+        lon: [9]float64{2.29, 0.36, 0.09, -1.89, 0.77, 1.35, -0.59, -0.38, -0.03},
+        lat: [9]float64{0.08, 0.14, -0.00, 0.01, -0.01, 0.00, -0.00, 0.00, -0.00},
+        dist: [9]float64{-4.10, -7.87, -0.04, 0.01, -0.05, 0.02, -0.03, 0.01, -0.02},
+        terms: []keplerTerm{
+            {2, earthBary, -2, [6]float64{-6.08, -9.48, -0.24, 0.14, 0.01, 0.02}, [6]float64{-0.01, -0.00, 0.00, -0.00, -0.00, -0.00}, [6]float64{-13.70, 8.78, 0.21, 0.35, 0.03, -0.03}},
+            {1, earthBary, -1, [6]float64{-4.29, -2.34, -0.04, 0.05, -0.00, 0.01}, [6]float64{-0.00, 0.00, 0.00, -0.00, 0.00, -0.00}, [6]float64{-1.79, 3.26, 0.05, 0.04, 0.01, -0.02}},
+            {1, SE_JUPITER, -1, [6]float64{1.37, -2.67, -0.09, -0.03, 0.01, -0.07}, [6]float64{0.03, 0.02, 0.01, 0.00, 0.00, 0.02}, [6]float64{-4.47, -2.31, -0.06, 0.16, -0.10, -0.03}},
+            {1, 0, 0, [6]float64{-1.94, 2.01, -5.61, -0.15, 0.15, 0.13}, [6]float64{-0.33, -0.17, -0.45, -0.29, -0.17, -0.17}, [6]float64{3.57, 3.41, -0.25, 9.89, 0.22, -0.28}},
+            {1, SE_JUPITER, 3, [6]float64{-0.10, 0.10, 6.45, 6.46, 4.50, -4.50}, [6]float64{0.00, -0.00, -0.05, -0.10, -0.07, 0.04}, [6]float64{0.21, 0.20, 12.47, -12.49, -8.70, -8.70}},
+            {2, SE_JUPITER, -2, [6]float64{-0.51, -0.72, -0.01, -0.01, 0.02, -0.01}, [6]float64{0.01, -0.01, -0.00, 0.00, 0.00, -0.00}, [6]float64{-1.79, 1.28, -0.03, 0.02, -0.01, -0.04}},
+            {0, SE_JUPITER, 1, [6]float64{-1.59, -0.01, -0.04, -0.03, -0.04, 0.06}, [6]float64{0.04, -0.07, 0.00, 0.00, -0.00, 0.00}, [6]float64{-0.04, 0.15, 0.00, 0.01, -0.01, -0.02}},
+            {3, earthBary, -5, [6]float64{-0.96, -1.01, -0.07, 0.03, 0.07, 0.04}, [6]float64{-0.03, -0.03, -0.00, 0.00, -0.00, -0.00}, [6]float64{0.19, -0.17, 0.01, -0.00, -0.02, 0.01}},
+            {0, SE_JUPITER, 3, [6]float64{0.01, -0.12, -4.42, -0.31, -0.22, 3.00}, [6]float64{0.00, 0.00, 0.36, -0.06, -0.04, -0.25}, [6]float64{-0.04, -0.00, -0.20, 1.59, 1.11, 0.14}},
+            {3, earthBary, -4, [6]float64{-0.18, -0.65, -0.03, 0.00, -0.00, -0.01}, [6]float64{0.06, 0.09, 0.01, -0.00, 0.00, 0.00}, [6]float64{-0.73, 0.19, 0.02, 0.04, -0.02, -0.01}},
+            {1, SE_JUPITER, -2, [6]float64{-0.11, -0.44, 0.05, 0.05, -0.05, 0.04}, [6]float64{-0.18, 0.04, -0.02, -0.00, 0.00, -0.00}, [6]float64{-0.70, 0.17, 0.08, -0.10, 0.09, 0.08}},
+            {3, earthBary, -2, [6]float64{-0.05, -0.08, 0.00, -0.00, -0.00, 0.01}, [6]float64{0.12, -0.28, -0.00, -0.00, 0.00, 0.00}, [6]float64{-0.11, 0.07, -0.00, -0.00, 0.02, 0.00}},
+            {1, earthBary, -2, [6]float64{-0.01, 0.10, 0.00, 0.01, -0.03, 0.02}, [6]float64{-0.24, -0.01, 0.00, 0.01, 0.00, 0.00}, [6]float64{0.04, -0.10, 0.00, 0.01, -0.01, -0.04}},
+            {2, SE_MERCURY, -1, [6]float64{-0.10, 0.25, -0.00, -0.01, -0.02, -0.00}, [6]float64{-0.00, -0.00, -0.00, 0.00, 0.00, 0.00}, [6]float64{-0.27, -0.11, 0.01, 0.02, 0.00, -0.03}},
+            {2, 0, 0, [6]float64{-0.02, 0.04, -0.04, 0.01, -0.00, 0.01}, [6]float64{-0.08, -0.02, -0.09, -0.14, 0.00, 0.00}, [6]float64{0.05, 0.03, -0.00, 0.05, 0.02, -0.00}},
+            {2, SE_JUPITER, -1, [6]float64{0.01, -0.01, 0.01, 0.01, -0.02, 0.00}, [6]float64{0.09, -0.01, -0.00, -0.00, 0.00, -0.00}, [6]float64{0.02, -0.02, 0.02, -0.03, 0.03, 0.05}},
+        },
+    },
+    earthBary: {
+        lon: [9]float64{0.18, 2.91, 0.51, 1.29, -0.65, -0.99, 0.27, 0.24, -0.05},
+        lat: [9]float64{-0.01, -0.03, -0.00, -0.00, -0.00, -0.00, -0.00, -0.00, -0.00},
+        dist: [9]float64{-2.34, -11.21, 0.13, -0.00, 0.15, -0.02, 0.10, -0.00, 0.07},
+        terms: []keplerTerm{
+            {1, SE_JUPITER, -1, [6]float64{-0.06, -7.12, 0.09, -0.00, 0.04, 0.13}, [6]float64{0.02, -0.01, -0.01, 0.00, -0.00, -0.02}, [6]float64{-16.09, 0.15, 0.01, -0.19, 0.27, -0.05}},
+            {2, SE_VENUS, -2, [6]float64{2.95, -4.63, 0.13, 0.08, -0.02, 0.03}, [6]float64{0.01, -0.01, -0.00, -0.00, 0.00, -0.00}, [6]float64{13.21, 8.45, -0.21, 0.35, -0.07, -0.03}},
+            {1, SE_VENUS, -1, [6]float64{-4.24, 2.31, -0.01, -0.03, -0.01, -0.01}, [6]float64{-0.01, 0.00, 0.00, 0.00, -0.00, -0.00}, [6]float64{-2.58, -4.74, 0.04, -0.01, 0.03, 0.02}},
+            {2, SE_JUPITER, -2, [6]float64{-2.72, 0.16, 0.03, -0.02, -0.00, 0.02}, [6]float64{0.00, -0.00, -0.00, -0.00, 0.00, -0.00}, [6]float64{0.56, 9.22, -0.06, -0.07, 0.04, 0.01}},
+            {0, SE_JUPITER, 1, [6]float64{-2.66, -0.30, -0.04, -0.09, -0.09, 0.02}, [6]float64{0.00, -0.02, -0.00, -0.00, 0.00, -0.00}, [6]float64{-0.31, 0.58, -0.03, 0.01, 0.05, 0.09}},
+            {2, SE_MARS, -2, [6]float64{0.59, 1.93, 0.05, -0.06, 0.01, -0.05}, [6]float64{-0.01, 0.01, -0.00, 0.02, -0.00, 0.01}, [6]float64{4.46, -1.39, -0.12, -0.07, -0.09, -0.05}},
+            {1, SE_JUPITER, -2, [6]float64{-1.53, -0.54, -0.07, -0.06, -0.02, -0.02}, [6]float64{0.17, -0.01, 0.01, -0.00, 0.00, 0.00}, [6]float64{-1.15, 3.11, -0.12, 0.18, -0.07, 0.05}},
+            {1, SE_MARS, -2, [6]float64{0.59, -1.71, 0.01, -0.05, -0.04, -0.09}, [6]float64{0.00, 0.00, -0.00, 0.00, -0.00, 0.00}, [6]float64{0.28, 0.15, 0.05, 0.06, 0.01, -0.01}},
+            {1, 0, 0, [6]float64{-1.13, 0.02, 1.64, 0.41, 0.03, 0.02}, [6]float64{0.40, 0.30, 8.23, 1.48, 0.34, 0.24}, [6]float64{0.09, 2.93, 0.99, -3.79, 0.04, -0.03}},
+        },
+    },
+    SE_MARS: {
+        lon: [9]float64{-5.21, -12.07, -5.36, -0.68, 0.15, 0.18, 0.34, 0.02, 0.08},
+        lat: [9]float64{0.03, 0.13, -0.02, -0.00, 0.01, 0.00, 0.01, 0.00, 0.00},
+        dist: [9]float64{-20.88, -33.18, -0.18, 0.34, -0.12, 0.30, -0.09, 0.22, -0.07},
+        terms: []keplerTerm{
+            {1, SE_JUPITER, -1, [6]float64{-19.24, 16.48, -0.19, -0.13, -0.19, -0.31}, [6]float64{-0.09, -0.05, -0.00, 0.00, -0.01, -0.01}, [6]float64{52.73, 61.41, -0.44, 0.55, -1.06, 0.81}},
+            {2, SE_JUPITER, -2, [6]float64{3.27, -15.62, 0.32, 0.05, -0.04, 0.08}, [6]float64{-0.26, -0.17, 0.00, -0.02, -0.01, 0.00}, [6]float64{-72.81, -15.59, 0.31, -1.38, 0.43, 0.05}},
+            {1, SE_JUPITER, -2, [6]float64{3.23, -21.81, 0.33, -0.00, 0.13, -0.28}, [6]float64{0.51, -0.33, -0.01, -0.01, 0.00, -0.00}, [6]float64{-55.28, -7.89, 0.02, -0.80, -1.05, -0.28}},
+            {1, earthBary, -1, [6]float64{4.79, 7.23, -0.86, -0.26, -0.09, 0.13}, [6]float64{-0.11, 0.07, -0.03, -0.00, -0.01, 0.01}, [6]float64{-21.16, 13.54, 0.72, -2.73, -0.33, -0.24}},
+            {2, earthBary, -1, [6]float64{4.74, 13.34, -0.50, 0.29, -0.11, 0.59}, [6]float64{0.15, -0.05, -0.00, 0.02, 0.00, 0.01}, [6]float64{10.15, -4.23, 0.19, 0.56, 0.28, 0.38}},
+            {3, earthBary, -2, [6]float64{2.82, -6.78, 0.08, -0.04, 0.16, 0.11}, [6]float64{0.02, -0.01, -0.00, -0.00, 0.00, 0.01}, [6]float64{21.07, 8.83, 0.15, 0.31, -0.54, 0.49}},
+            {1, 0, 0, [6]float64{4.22, -3.03, 9.94, -1.92, -0.10, -1.13}, [6]float64{0.06, 0.12, 0.11, 0.24, -0.06, 0.15}, [6]float64{-11.52, -9.98, -7.21, -33.06, -4.11, 0.46}},
+            {2, SE_JUPITER, -3, [6]float64{1.75, -1.88, -0.07, 0.21, 0.01, -0.01}, [6]float64{-0.01, -0.01, -0.01, -0.01, -0.01, 0.00}, [6]float64{-7.97, -8.09, 0.97, 0.49, 0.04, -0.11}},
+            {2, SE_JUPITER, -1, [6]float64{-2.34, 2.18, -0.00, -0.07, -0.09, 0.05}, [6]float64{0.18, 0.34, -0.01, -0.00, 0.01, 0.01}, [6]float64{6.96, 7.24, -0.41, 0.02, 0.02, 0.32}},
+            {0, SE_JUPITER, 1, [6]float64{-0.91, 3.62, 0.09, 0.33, 0.34, 0.06}, [6]float64{0.23, -0.24, 0.01, 0.01, -0.00, -0.00}, [6]float64{7.15, -5.69, 0.47, 0.06, -0.09, -0.42}},
+            {3, SE_VENUS, -1, [6]float64{4.51, 2.78, -0.40, 0.23, -1.38, -1.23}, [6]float64{-0.00, 0.02, -0.00, 0.00, 0.01, -0.00}, [6]float64{-0.96, 1.66, -0.02, -0.03, 0.40, -0.34}},
+            {1, SE_JUPITER, -3, [6]float64{2.35, -2.37, 0.51, -0.60, -0.13, -0.01}, [6]float64{0.07, 0.00, -0.00, -0.00, -0.00, -0.00}, [6]float64{-4.74, -4.77, -1.47, -1.11, -0.21, 0.21}},
+            {0, SE_JUPITER, 2, [6]float64{-0.09, -1.53, -0.01, -0.17, 0.23, -0.13}, [6]float64{-0.33, 0.00, -0.00, 0.00, -0.01, -0.01}, [6]float64{-8.24, 1.09, -0.23, 0.15, -0.45, -0.23}},
+            {3, SE_JUPITER, -3, [6]float64{-0.36, -1.35, -0.04, 0.09, 0.04, 0.03}, [6]float64{-0.02, -0.04, 0.00, -0.00, 0.00, 0.00}, [6]float64{-7.29, 2.78, 0.38, 0.13, 0.11, -0.13}},
+            {3, SE_JUPITER, -2, [6]float64{0.47, -2.04, 0.05, 0.04, 0.00, 0.03}, [6]float64{-0.25, -0.14, 0.01, -0.01, 0.00, -0.00}, [6]float64{-6.60, -1.59, 0.07, -0.19, 0.21, 0.03}},
+            {3, earthBary, -1, [6]float64{0.49, 1.41, 0.16, -0.07, -0.14, -0.03}, [6]float64{0.18, -0.00, 0.00, 0.01, 0.01, 0.00}, [6]float64{4.88, -1.80, -0.30, -0.53, -0.29, 0.42}},
+        },
+    },
+    SE_JUPITER: {
+        lon: [9]float64{-66.83, -101.86, -57.11, 112.97, -0.27, -2.53, -1.70, 0.30, -0.70},
+        lat: [9]float64{-0.16, 0.76, -0.76, 0.22, -0.79, 0.03, -0.57, 0.03, -0.28},
+        dist: [9]float64{-3.30, 313.04, -163.98, 8.25, -55.76, -0.93, -43.19, -1.45, -21.42},
+        terms: []keplerTerm{
+            {0, SE_SATURN, 3, [6]float64{-8.52, 63.88, 108.96, 5.88, -0.81, -28.72}, [6]float64{-0.08, -1.60, 2.02, -1.87, -0.88, -1.58}, [6]float64{935.95, 146.05, 59.82, -1482.51, -359.56, 38.72}},
+            {1, SE_SATURN, -2, [6]float64{-129.05, 2.79, -13.90, -4.08, 0.63, -3.44}, [6]float64{0.14, -1.36, -0.82, -1.12, 0.70, -0.37}, [6]float64{59.11, 285.51, -40.33, 0.60, 23.66, -21.58}},
+            {1, SE_SATURN, -1, [6]float64{-14.82, 75.70, -3.74, -1.67, -0.13, -1.67}, [6]float64{-0.04, -0.25, -0.33, -0.01, -0.24, -0.40}, [6]float64{607.65, 118.00, -22.82, 36.25, -28.48, -6.24}},
+            {0, SE_SATURN, 2, [6]float64{13.97, -27.74, -86.52, -29.71, -54.05, 33.22}, [6]float64{-1.49, -2.54, -1.73, 3.62, -4.29, -0.47}, [6]float64{-340.52, -156.19, -275.75, 1029.60, 265.40, 589.02}},
+            {1, 0, 0, [6]float64{27.48, 44.92, 55.08, 17.64, 14.33, 61.50}, [6]float64{-1.77, 1.63, -4.80, 1.94, -1.07, 1.27}, [6]float64{562.57, -339.72, 211.81, -728.02, 765.09, -187.27}},
+            {1, SE_SATURN, 2, [6]float64{9.63, 1.00, 1.68, -22.32, -7.89, -0.22}, [6]float64{-0.21, -0.48, -0.76, 0.80, 0.48, 0.30}, [6]float64{28.66, -154.03, -362.66, -57.86, -20.88, 134.36}},
+            {1, SE_SATURN, 1, [6]float64{3.42, 5.92, 12.15, -14.60, -6.87, -4.45}, [6]float64{0.19, 0.19, -0.12, -0.28, -0.25, 0.09}, [6]float64{93.79, -60.40, -216.27, -194.92, -67.72, 90.13}},
+            {1, SE_SATURN, 3, [6]float64{-0.37, 6.60, 15.40, 2.13, 1.42, -5.25}, [6]float64{1.14, 0.25, 0.44, -2.71, -1.00, -0.06}, [6]float64{65.38, 2.89, 28.43, -157.61, -58.72, -21.31}},
+            {4, SE_SATURN, -7, [6]float64{37.25, -34.43, 73.55, 58.13, -43.59, 57.27}, [6]float64{-1.04, 3.86, 2.30, -0.25, 0.13, 3.04}, [6]float64{-521.73, -589.88, 768.99, -1009.13, 777.41, 573.20}},
+            {0, SE_SATURN, 1, [6]float64{0.40, 5.55, -7.51, -17.63, -6.14, 2.61}, [6]float64{1.45, -0.46, 0.09, 0.07, 0.16, -0.18}, [6]float64{64.52, -18.75, -90.40, 55.31, 32.40, 22.03}},
+            {2, SE_SATURN, -1, [6]float64{0.89, 5.96, 0.73, -2.26, 0.44, 1.33}, [6]float64{1.24, 0.20, 0.03, -0.06, 0.12, 0.02}, [6]float64{70.46, -17.45, -36.77, -4.49, 18.86, -3.95}},
+            {2, 0, 0, [6]float64{2.28, 2.77, 2.16, -1.50, -0.81, 5.36}, [6]float64{0.50, -0.10, -0.01, -0.62, 0.67, 0.16}, [6]float64{20.98, -34.92, -37.52, -11.44, 54.98, 13.39}},
+            {2, SE_SATURN, 1, [6]float64{1.08, -1.04, -3.25, -3.23, -1.41, 1.46}, [6]float64{0.05, -0.04, -0.13, -0.21, -0.10, 0.04}, [6]float64{-24.82, -21.98, -52.25, 69.46, 30.37, 19.50}},
+            {1, SE_URANUS, 1, [6]float64{14.83, -4.69, -0.07, 17.74, -1.45, -1.72}, [6]float64{0.72, -1.08, -2.08, -2.31, 1.35, -1.53}, [6]float64{-60.03, -201.81, 309.50, 6.76, -19.85, 41.55}},
+            {1, SE_URANUS, -2, [6]float64{-1.48, -3.28, -2.63, 0.29, -2.44, -5.33}, [6]float64{-0.62, -0.15, -0.08, 0.32, -0.84, -0.22}, [6]float64{-51.33, 19.63, 4.40, 39.93, -78.58, 30.71}},
+            {1, SE_URANUS, -1, [6]float64{5.83, 7.09, -23.51, 28.74, 17.93, 15.01}, [6]float64{0.80, 0.91, -3.84, 3.42, 2.11, 2.30}, [6]float64{103.61, -52.35, 262.49, 355.77, 229.12, -162.39}},
+        },
+    },
+    SE_SATURN: {
+        lon: [9]float64{242.55, 308.73, 176.15, -341.18, 34.72, -75.09, -7.65, -63.62, -27.23},
+        lat: [9]float64{4.49, -1.56, 5.51, -5.02, 5.07, -4.63, 4.64, -3.58, 3.47},
+        dist: [9]float64{5600.43, 3436.08, 2716.46, 992.95, 975.93, 1231.44, 633.56, 819.50, 197.75},
+        terms: []keplerTerm{
+            {2, SE_JUPITER, -1, [6]float64{-389.08, -54.25, -71.81, 32.37, 44.95, -12.92}, [6]float64{-0.61, -8.52, -10.40, 0.03, 2.26, 1.23}, [6]float64{705.65, -4893.41, -435.72, -1073.93, 142.67, 731.76}},
+            {1, 0, 0, [6]float64{254.40, 0.23, 250.02, -40.18, 114.40, 16.42}, [6]float64{-2.66, 10.17, 11.02, 12.49, -14.49, 9.21}, [6]float64{494.62, -6172.32, -925.53, -5607.12, 627.41, -2789.36}},
+            {1, SE_JUPITER, -1, [6]float64{-31.27, -19.02, 16.63, -5.65, -3.01, -21.25}, [6]float64{-6.82, -6.09, 1.47, 0.35, 2.50, -2.72}, [6]float64{-7464.44, 939.59, 472.41, 1045.14, 988.07, -378.44}},
+            {2, 0, 0, [6]float64{31.64, -13.53, -7.83, -25.64, 4.64, 7.41}, [6]float64{7.38, -3.04, 8.15, -2.19, 4.26, -3.04}, [6]float64{-426.63, -755.25, -904.09, 702.90, 339.23, 92.02}},
+            {3, SE_URANUS, -6, [6]float64{91.95, 47.01, 107.13, 144.94, -16.49, 83.22}, [6]float64{10.64, 4.89, -3.82, 9.42, 0.99, 1.69}, [6]float64{832.84, -2151.67, 3176.78, -2696.35, 1770.72, 266.90}},
+            {3, 0, 0, [6]float64{3.49, -13.12, -19.92, -5.09, -1.62, 29.52}, [6]float64{0.60, -0.75, 0.49, -0.73, 0.24, -0.25}, [6]float64{-556.05, -130.58, -42.56, 826.33, 1303.02, 134.69}},
+            {0, SE_JUPITER, 1, [6]float64{0.79, 9.18, -3.80, 0.53, 0.22, 0.82}, [6]float64{1.54, 0.83, -0.38, 0.39, -0.24, -0.03}, [6]float64{-143.84, -152.82, 24.24, 120.10, 35.04, -48.05}},
+            {3, SE_URANUS, -5, [6]float64{-0.13, -14.79, 19.52, 11.08, -8.31, -10.24}, [6]float64{-1.84, -1.85, 0.44, -0.41, -2.41, -2.22}, [6]float64{-437.20, -90.03, 306.91, -552.50, -319.15, 105.32}},
+            {2, SE_JUPITER, 1, [6]float64{-3.39, -1.03, -3.10, 7.64, 2.54, 1.19}, [6]float64{-0.04, 0.01, -0.02, 0.07, -0.03, 0.03}, [6]float64{-62.65, 162.42, 366.77, 148.36, 32.73, -125.84}},
+            {2, SE_URANUS, -5, [6]float64{-63.02, -38.50, -26.92, 33.03, -56.14, -19.25}, [6]float64{0.72, -3.06, -2.27, 0.58, -0.77, -1.65}, [6]float64{33.80, 835.64, 505.50, -4.45, 4.86, 753.58}},
+            {2, SE_URANUS, -4, [6]float64{-1.19, -14.00, -8.92, 7.46, 13.16, 2.81}, [6]float64{-1.21, -1.22, -1.83, -2.05, -2.23, 0.34}, [6]float64{-277.98, 26.61, -244.40, 314.12, 59.00, -74.21}},
+            {1, SE_JUPITER, 1, [6]float64{-1.51, -0.98, -4.16, 4.56, 1.42, 2.19}, [6]float64{0.03, 0.03, -0.22, 0.25, 0.05, -0.01}, [6]float64{-55.51, 62.75, 203.41, 189.79, 96.11, -55.35}},
+            {1, SE_JUPITER, -2, [6]float64{-1.68, -2.08, 0.27, 0.56, 0.41, -0.51}, [6]float64{-0.39, 0.15, 0.08, 0.18, 0.05, -0.01}, [6]float64{-95.16, 10.38, -17.82, 9.80, 43.66, 4.89}},
+            {3, SE_URANUS, -4, [6]float64{1.41, -5.34, -10.16, -17.79, -2.70, -1.39}, [6]float64{-1.13, -1.45, -3.30, -1.63, -1.78, -0.20}, [6]float64{-106.47, -79.46, -1007.81, 462.28, 89.34, 240.51}},
+            {1, SE_JUPITER, 2, [6]float64{-0.46, 1.00, 2.05, 1.31, 0.59, -0.51}, [6]float64{-0.00, 0.02, 0.03, -0.01, -0.00, 0.02}, [6]float64{46.85, 33.93, 68.43, -96.52, -30.45, -10.03}},
+            {1, SE_URANUS, 6, [6]float64{-2.72, -3.49, -15.50, 12.20, 3.82, 4.94}, [6]float64{0.21, -0.05, 0.06, 0.32, 0.39, -0.15}, [6]float64{-156.02, 125.58, 437.03, 815.04, 273.37, -114.63}},
+        },
+    },
+    SE_URANUS: {
+        lon: [9]float64{126.27, 170.87, 75.98, -79.85, -67.53, -92.96, 3.37, -16.41, 6.86},
+        lat: [9]float64{0.17, -0.29, 0.33, 0.88, 1.75, 1.20, 2.70, 1.21, 2.65},
+        dist: [9]float64{1741.76, -823.23, 65.62, -2814.55, -1392.24, -3565.45, -3607.87, -1729.03, -2205.53},
+        terms: []keplerTerm{
+            {1, SE_JUPITER, -1, [6]float64{43.13, -21.78, -0.95, 2.39, -7.47, -0.62}, [6]float64{0.04, -0.04, 0.07, 0.15, 0.02, 0.02}, [6]float64{-1969.70, -4354.54, 371.77, -322.30, 141.33, 361.68}},
+            {1, 0, 0, [6]float64{19.77, 48.12, 32.88, 164.19, 7.25, 25.74}, [6]float64{-0.83, 0.21, -4.23, 1.90, -0.19, -0.31}, [6]float64{1359.93, -78.97, 6380.45, -3473.07, -112.79, 562.68}},
+            {2, 0, 0, [6]float64{-9.37, -2.36, 1.61, 18.06, 5.49, -9.94}, [6]float64{-0.32, 0.21, -0.62, 0.58, -0.04, -0.10}, [6]float64{40.75, -1014.90, -4911.79, 224.96, 215.43, 1802.99}},
+            {1, SE_NEPTUNE, 1, [6]float64{-16.64, -26.80, -18.22, -32.69, -46.05, -35.56}, [6]float64{1.11, -0.56, 1.91, -0.68, 1.28, -0.81}, [6]float64{-727.27, -466.01, -2757.01, -738.43, -484.01, 515.16}},
+            {2, SE_JUPITER, -1, [6]float64{2.62, -5.13, -3.06, -1.68, -0.23, -5.64}, [6]float64{0.10, 0.34, -0.11, 0.21, -0.07, -0.04}, [6]float64{-99.52, -272.55, 179.02, -189.42, 76.67, 5.30}},
+            {0, SE_JUPITER, 1, [6]float64{4.24, 1.54, 5.61, -2.66, 3.45, -0.12}, [6]float64{-0.14, -0.87, -0.19, 0.11, -0.04, 0.09}, [6]float64{-14.07, 231.99, 269.08, 156.59, 168.78, -55.29}},
+            {1, SE_SATURN, 1, [6]float64{-2.38, -3.09, -7.78, -1.76, -3.13, 0.14}, [6]float64{0.07, -0.01, 0.10, 0.12, 0.04, 0.01}, [6]float64{-213.56, 35.55, -234.47, 266.64, 27.18, 101.91}},
+            {3, 0, 0, [6]float64{6.17, -4.32, 4.92, -5.45, 6.61, -6.02}, [6]float64{-0.38, -0.17, -0.98, 1.23, 0.24, 0.39}, [6]float64{250.11, -349.10, 342.95, -212.45, 328.39, -598.30}},
+            {1, SE_JUPITER, -2, [6]float64{0.90, -0.49, -0.22, 0.01, -0.45, 0.05}, [6]float64{0.01, 0.00, 0.00, -0.00, 0.01, 0.01}, [6]float64{-60.41, -108.40, -8.79, 1.27, -20.50, 0.99}},
+            {1, SE_SATURN, -2, [6]float64{-0.24, -2.50, -1.88, -3.43, -1.23, -0.90}, [6]float64{0.03, 0.16, -0.19, 0.08, 0.08, 0.16}, [6]float64{-61.81, -114.79, 178.06, -17.76, -32.21, -231.77}},
+            {3, SE_NEPTUNE, 1, [6]float64{4.04, -2.74, 5.50, 0.12, 3.75, -4.25}, [6]float64{0.01, 0.15, -0.16, -0.05, 0.05, 0.15}, [6]float64{3.92, -257.41, 548.28, -53.70, -118.49, -196.10}},
+            {2, SE_NEPTUNE, 1, [6]float64{-0.03, -10.83, -0.98, -11.17, -0.88, -14.70}, [6]float64{0.33, 0.04, -0.02, -0.08, 0.53, -0.22}, [6]float64{-164.21, -260.09, 13.27, 81.95, -206.17, -318.97}},
+            {0, SE_SATURN, 2, [6]float64{1.49, -2.28, 3.29, 3.76, 1.40, -1.50}, [6]float64{-0.06, 0.03, 0.01, 0.13, -0.15, 0.06}, [6]float64{54.13, -176.34, -331.74, 21.19, 81.18, -459.18}},
+            {2, SE_JUPITER, -2, [6]float64{0.02, -0.40, -0.16, -0.18, -0.33, -0.35}, [6]float64{0.01, 0.02, 0.00, 0.00, 0.01, 0.02}, [6]float64{5.00, 1.65, -7.62, -5.82, -16.13, -15.26}},
+            {1, SE_SATURN, 2, [6]float64{5.01, 0.54, 3.13, -2.26, 6.89, 1.60}, [6]float64{-0.11, -0.03, -0.13, 0.16, -0.17, -0.05}, [6]float64{34.99, 186.56, 248.03, 7.79, 30.71, 328.84}},
+            {1, SE_SATURN, -3, [6]float64{1.78, 0.66, 1.27, -1.45, 2.66, 1.07}, [6]float64{-0.04, -0.03, -0.03, 0.01, -0.06, -0.05}, [6]float64{47.85, -6.20, 16.28, -76.82, 99.30, -12.89}},
+        },
+    },
+    SE_NEPTUNE: {
+        lon: [9]float64{-43.40, -68.91, -37.80, -0.95, 15.64, 16.79, 6.69, -4.67, -3.70},
+        lat: [9]float64{0.53, 1.44, 1.07, 0.53, -0.26, -0.68, -1.08, -0.81, -0.33},
+        dist: [9]float64{2043.90, 182.81, -666.45, -1453.36, -1393.68, -223.99, -966.20, 1171.18, 406.21},
+        terms: []keplerTerm{
+            {1, SE_JUPITER, -1, [6]float64{-29.44, -17.02, -0.17, 0.58, -0.19, 0.13}, [6]float64{0.26, 0.00, 0.04, 0.02, 0.43, 0.02}, [6]float64{-2461.35, 4290.76, 73.65, 52.39, 50.92, 23.00}},
+            {1, SE_SATURN, -1, [6]float64{-12.55, 13.75, 0.27, -1.09, -0.27, 0.16}, [6]float64{0.17, -0.24, 0.06, 0.00, 0.29, -0.41}, [6]float64{2075.30, 1774.09, 123.30, 21.77, 67.94, -138.25}},
+            {2, 0, 0, [6]float64{7.48, 4.36, 8.03, -3.57, 1.19, -3.87}, [6]float64{0.39, -0.06, 0.41, 0.12, 0.32, -0.07}, [6]float64{279.84, -577.11, -646.70, -1105.90, -718.59, 265.45}},
+        },
+    },
+    SE_PLUTO: {
+        lon: [9]float64{-69.71, -135.17, -95.91, -58.97, -17.82, -16.31, -5.47, -5.14, -2.77},
+        lat: [9]float64{-24.09, -43.41, -32.04, -18.46, -8.16, 0.64, 2.65, 2.22, 2.07},
+        dist: [9]float64{9186.54, 14589.93, 7793.61, 2856.78, -1783.60, -1178.41, -523.69, -419.99, -966.39},
+        terms: []keplerTerm{
+            {1, SE_JUPITER, -1, [6]float64{19.47, 10.08, 2.95, -2.50, -4.45, -3.71}, [6]float64{1.20, -0.74, -1.11, 2.26, 0.58, 0.24}, [6]float64{1942.52, -3539.19, -663.86, -127.35, -449.92, 954.65}},
+            {1, SE_SATURN, -1, [6]float64{5.98, -9.62, -1.97, -3.49, -2.80, 1.45}, [6]float64{-0.46, -1.50, 3.05, 1.75, -0.04, -0.20}, [6]float64{-1524.05, -1197.36, -600.30, 505.42, 531.31, 479.73}},
+            {2, SE_JUPITER, -1, [6]float64{6.35, 3.25, 2.47, 1.88, -1.24, 0.03}, [6]float64{-2.65, 1.40, 0.26, 3.50, -1.26, -0.58}, [6]float64{418.12, -707.03, 368.55, 72.65, -125.04, 197.91}},
+            {0, SE_JUPITER, 1, [6]float64{2.69, -1.93, -4.00, -2.56, -1.55, 1.48}, [6]float64{0.59, 2.48, 1.30, 1.21, 0.64, -1.52}, [6]float64{688.49, 919.10, 730.71, -473.80, -104.51, -495.68}},
+            {2, SE_NEPTUNE, 3, [6]float64{-0.93, 2.12, 0.82, 0.58, -4.28, -3.22}, [6]float64{0.04, -0.37, 1.54, 0.09, -1.72, 1.88}, [6]float64{-80.76, 34.68, -104.71, -0.58, 646.96, -312.61}},
+            {0, SE_SATURN, 1, [6]float64{1.79, 0.86, 1.44, -2.17, -0.39, -1.21}, [6]float64{-0.92, 0.29, -0.60, 1.36, 1.15, 0.10}, [6]float64{-458.03, 567.71, 483.45, 332.97, 205.16, -67.75}},
+            {1, SE_SATURN, 2, [6]float64{-1.22, -1.16, -1.37, -1.49, 3.25, -1.28}, [6]float64{-0.18, 0.50, -2.40, 1.22, -1.08, -2.79}, [6]float64{60.83, -181.78, -86.40, -84.08, 18.93, 102.01}},
+            {2, 0, 0, [6]float64{2.99, 10.12, 2.54, -0.39, 2.55, 8.43}, [6]float64{-1.04, -1.42, -0.75, -1.51, -0.44, 0.63}, [6]float64{83.76, 1462.91, 146.77, 106.17, 455.51, 482.97}},
+        },
+    },
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+    "math"
+)
+
+// ===== Chart points and aspects =====
+
+type aspectDef struct {
+    Name  string
+    Angle float64
+}
+
+// Major (Ptolemaic) aspects.
+var majorAspects = []aspectDef{
+    {"conjunction", 0},
+    {"sextile", 60},
+    {"square", 90},
+    {"trine", 120},
+    {"opposition", 180},
+}
+
+type chartBody struct {
+    Name string
+    ID   int
+}
+
+// chartBodies are the points Chiron is aspected to, besides the angles.
+var chartBodies = []chartBody{
+    {"Sun", SE_SUN}, {"Moon", SE_MOON}, {"Mercury", SE_MERCURY}, {"Venus", SE_VENUS},
+    {"Mars", SE_MARS}, {"Jupiter", SE_JUPITER}, {"Saturn", SE_SATURN},
+    {"Uranus", SE_URANUS}, {"Neptune", SE_NEPTUNE}, {"Pluto", SE_PLUTO},
+}
+
+type chartPoint struct {
+    Name string
+    Lon  float64
+}
+
+// bodySpeedAt returns a body's longitude and daily speed. The caller must
+// already be inside withEphemeris.
+func bodySpeedAt(jd float64, body, flags int) (lon, speed float64, err error) {
+    xx := make([]float64, 6)
+    if err := eph.CalcUt(jd, body, flags, xx); err != nil {
+        return 0, 0, err
+    }
+    return xx[0], xx[3], nil
+}
+
+// computeBodyLongitude returns the longitude of any swe body in the chosen zodiac.
+func computeBodyLongitude(jd float64, body int, opts calcOptions) (float64, error) {
+    xx := make([]float64, 6)
+    var err error
+    withEphemeris(opts, func() {
+        err = eph.CalcUt(jd, body, opts.flags(), xx)
+    })
+    if err != nil {
+        ephemerisErrors.inc("bodies")
+        return 0, err
+    }
+    return xx[0], nil
+}
+
+// computeChartPoints returns the planets at jd, in chartBodies order.
+func computeChartPoints(jd float64, opts calcOptions) ([]chartPoint, error) {
+    points := make([]chartPoint, 0, len(chartBodies))
+    xx := make([]float64, 6)
+    var err error
+    withEphemeris(opts, func() {
+        for _, b := range chartBodies {
+            if err = eph.CalcUt(jd, b.ID, opts.flags(), xx); err != nil {
+                return
+            }
+            points = append(points, chartPoint{b.Name, xx[0]})
+        }
+    })
+    if err != nil {
+        ephemerisErrors.inc("bodies")
+    }
+    return points, err
+}
+
+// findAspect returns the major aspect between two longitudes within orb, and
+// how far from exact it is.
+func findAspect(a, b, orb float64) (aspectDef, float64, bool) {
+    sep := angleDistance(a, b)
+    for _, asp := range majorAspects {
+        if d := math.Abs(sep - asp.Angle); d <= orb {
+            return asp, d, true
+        }
+    }
+    return aspectDef{}, 0, false
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+    "math"
+    "net/http"
+    "sort"
+    "time"
+)
+
+// ===== Astrocartography =====
+
+// Lines are drawn in mundo from Chiron's right ascension and declination:
+// the MC/IC lines are meridians, the ASC/DSC lines are where Chiron rises or
+// sets. Latitudes beyond ±acgLatLimit are left out, as on most ACG maps.
+const (
+    acgLatLimit    = 80.0
+    acgMapStep     = 1.0  // degrees of latitude between GeoJSON points
+    acgNearestStep = 0.25 // finer sampling for distance queries
+    earthRadiusKm  = 6371.0088
+)
+
+var acgAngles = []string{"ascendant", "descendant", "midheaven", "imum_coeli"}
+
+type GeoJSONGeometry struct {
+    Type        string         `json:"type"`        // MultiLineString: split where a line crosses the antimeridian
+    Coordinates [][][2]float64 `json:"coordinates"` // [lon, lat]
+}
+
+type GeoJSONFeature struct {
+    Type       string                 `json:"type"`
+    Geometry   GeoJSONGeometry        `json:"geometry"`
+    Properties map[string]interface{} `json:"properties"`
+}
+
+type GeoJSONFeatureCollection struct {
+    Type     string           `json:"type"`
+    Features []GeoJSONFeature `json:"features"`
+}
+
+type NearestLine struct {
+    Angle        string     `json:"angle"`
+    DistanceKm   float64    `json:"distance_km"`
+    NearestPoint [2]float64 `json:"nearest_point"` // [lon, lat]
+}
+
+type nearestLineRequest struct {
+    BirthData
+    CityLat *float64 `json:"city_lat"` // required: 0 is a real place, so absence must show
+    CityLon *float64 `json:"city_lon"`
+}
+
+// chironMundo returns Chiron's geocentric right ascension and declination and
+// the Greenwich apparent sidereal time, all in degrees.
+func chironMundo(jd float64) (ra, dec, gast float64, err error) {
+    pos, err := computeChironPosition(jd, tropical)
+    if err != nil {
+        return 0, 0, 0, err
+    }
+    withEphemeris(tropical, func() {
+        gast = eph.Sidtime(jd) * 15
+    })
+    return pos.RA, pos.Dec, gast, nil
+}
+
+func norm180(x float64) float64 {
+    x = math.Mod(x+180, 360)
+    if x < 0 {
+        x += 360
+    }
+    return x - 180
+}
+
+// acgLines samples the four lines every step degrees of latitude.
+func acgLines(ra, dec, gast, step float64) map[string][][][2]float64 {
+    lines := map[string][][][2]float64{}
+    add := func(angle string, lon, lat float64) {
+        segs := lines[angle]
+        if n := len(segs); n > 0 {
+            last := segs[n-1][len(segs[n-1])-1]
+            if math.Abs(lon-last[0]) <= 180 {
+                segs[n-1] = append(segs[n-1], [2]float64{lon, lat})
+                return
+            }
+        }
+        lines[angle] = append(segs, [][2]float64{{lon, lat}})
+    }
+
+    mc := norm180(ra - gast)
+    tanDec := math.Tan(dec * math.Pi / 180)
+    for lat := -acgLatLimit; lat <= acgLatLimit+1e-9; lat += step {
+        add("midheaven", round6(mc), lat)
+        add("imum_coeli", round6(norm180(mc+180)), lat)
+
+        // Hour angle of rising/setting; none when Chiron is circumpolar here
+        x := -math.Tan(lat*math.Pi/180) * tanDec
+        if math.Abs(x) > 1 {
+            continue
+        }
+        h0 := math.Acos(x) * 180 / math.Pi
+        add("ascendant", round6(norm180(mc-h0)), lat)
+        add("descendant", round6(norm180(mc+h0)), lat)
+    }
+    return lines
+}
+
+// haversineKm is the great-circle distance between two [lon, lat] points.
+func haversineKm(a, b [2]float64) float64 {
+    const rad = math.Pi / 180
+    dLat := (b[1] - a[1]) * rad
+    dLon := (b[0] - a[0]) * rad
+    h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(a[1]*rad)*math.Cos(b[1]*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+    return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// nearestOnLine finds the point of segs closest to p. Each segment between
+// samples is treated as straight in a local equirectangular projection
+// around p, which is accurate at the sampling step used.
+func nearestOnLine(segs [][][2]float64, p [2]float64) ([2]float64, float64) {
+    cosLat := math.Cos(p[1] * math.Pi / 180)
+    project := func(q [2]float64) (float64, float64) {
+        return norm180(q[0]-p[0]) * cosLat, q[1] - p[1]
+    }
+    best, bestKm := [2]float64{}, math.Inf(1)
+    for _, seg := range segs {
+        for i := range seg {
+            a, b := seg[i], seg[i]
+            if i+1 < len(seg) {
+                b = seg[i+1]
+            }
+            ax, ay := project(a)
+            bx, by := project(b)
+            t := 0.0
+            if d := (bx-ax)*(bx-ax) + (by-ay)*(by-ay); d > 0 {
+                t = math.Max(0, math.Min(1, -(ax*(bx-ax)+ay*(by-ay))/d))
+            }
+            q := [2]float64{norm180(a[0] + t*norm180(b[0]-a[0])), a[1] + t*(b[1]-a[1])}
+            if km := haversineKm(p, q); km < bestKm {
+                best, bestKm = q, km
+            }
+        }
+    }
+    return best, bestKm
+}
+
+// acgRequestJD validates the birth data used for astrocartography.
+func acgRequestJD(w http.ResponseWriter, req BirthData) (float64, bool) {
+    if req.UnknownTime {
+        http.Error(w, "astrocartography needs a birth time", http.StatusBadRequest)
+        return 0, false
+    }
+    utc, _, err := birthTime(req)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return 0, false
+    }
+    return julianDay(utc), true
+}
+
+// astrocartographyHandler returns Chiron's ASC/DSC/MC/IC lines as GeoJSON.
+func astrocartographyHandler(w http.ResponseWriter, r *http.Request) {
+    var req BirthData
+    if err := decodeJSON(w, r, &req); err != nil {
+        writeDecodeError(w, err)
+        return
+    }
+    jd, ok := acgRequestJD(w, req)
+    if !ok {
+        return
+    }
+
+    ephStart := time.Now()
+    ra, dec, gast, err := chironMundo(jd)
+    observeEphemeris(r.Context(), "chiron", ephStart)
+    if err != nil {
+        http.Error(w, "ephemeris unavailable", http.StatusInternalServerError)
+        return
+    }
+
+    lines := acgLines(ra, dec, gast, acgMapStep)
+    fc := GeoJSONFeatureCollection{Type: "FeatureCollection", Features: []GeoJSONFeature{}}
+    for _, angle := range acgAngles {
+        if len(lines[angle]) == 0 {
+            continue
+        }
+        fc.Features = append(fc.Features, GeoJSONFeature{
+            Type:     "Feature",
+            Geometry: GeoJSONGeometry{Type: "MultiLineString", Coordinates: lines[angle]},
+            Properties: map[string]interface{}{
+                "body":  "chiron",
+                "angle": angle,
+                "text":  angleTexts[angle],
+            },
+        })
+    }
+    writeJSON(w, http.StatusOK, fc)
+}
+
+// nearestLineHandler reports how far a city is from each of Chiron's lines.
+func nearestLineHandler(w http.ResponseWriter, r *http.Request) {
+    var req nearestLineRequest
+    if err := decodeJSON(w, r, &req); err != nil {
+        writeDecodeError(w, err)
+        return
+    }
+    if req.CityLat == nil || req.CityLon == nil {
+        http.Error(w, "city_lat and city_lon are required", http.StatusBadRequest)
+        return
+    }
+    cityLat, cityLon := *req.CityLat, *req.CityLon
+    if math.Abs(cityLat) > 90 || math.Abs(cityLon) > 180 {
+        http.Error(w, "city_lat/city_lon out of range", http.StatusBadRequest)
+        return
+    }
+    jd, ok := acgRequestJD(w, req.BirthData)
+    if !ok {
+        return
+    }
+
+    ephStart := time.Now()
+    ra, dec, gast, err := chironMundo(jd)
+    observeEphemeris(r.Context(), "chiron", ephStart)
+    if err != nil {
+        http.Error(w, "ephemeris unavailable", http.StatusInternalServerError)
+        return
+    }
+
+    city := [2]float64{cityLon, cityLat}
+    lines := acgLines(ra, dec, gast, acgNearestStep)
+    nearest := []NearestLine{}
+    for _, angle := range acgAngles {
+        if len(lines[angle]) == 0 {
+            continue
+        }
+        p, km := nearestOnLine(lines[angle], city)
+        nearest = append(nearest, NearestLine{
+            Angle:        angle,
+            DistanceKm:   math.Round(km*10) / 10,
+            NearestPoint: [2]float64{round6(p[0]), round6(p[1])},
+        })
+    }
+    sort.Slice(nearest, func(i, j int) bool { return nearest[i].DistanceKm < nearest[j].DistanceKm })
+
+    writeJSON(w, http.StatusOK, map[string]interface{}{
+        "city":  map[string]float64{"lat": cityLat, "lon": cityLon},
+        "lines": nearest,
+    })
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+    "math"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func TestNearestLineNeedsCity(t *testing.T) {
+    useFake(t)
+    birth := `"year": 1990, "month": 6, "day": 15, "hour": 14.5, "timezone": "Europe/London"`
+    for name, c := range map[string]struct {
+        body string
+        code int
+    }{
+        "no city":      {`{` + birth + `}`, http.StatusBadRequest},
+        "misspelled":   {`{` + birth + `, "city_lat": 40.7, "city_lng": -74}`, http.StatusBadRequest},
+        "out of range": {`{` + birth + `, "city_lat": 91, "city_lon": 0}`, http.StatusBadRequest},
+        "null island":  {`{` + birth + `, "city_lat": 0, "city_lon": 0}`, http.StatusOK},
+    } {
+        w := httptest.NewRecorder()
+        nearestLineHandler(w, httptest.NewRequest(http.MethodPost, "/api/astrocartography/nearest", strings.NewReader(c.body)))
+        if w.Code != c.code {
+            t.Errorf("%s: status %d %q, want %d", name, w.Code, strings.TrimSpace(w.Body.String()), c.code)
+        }
+    }
+}
+
+func TestACGLinesSplitAtAntimeridian(t *testing.T) {
+    // MC at 120°E, so the descendant runs from there across 180° in the south
+    lines := acgLines(120, 10, 0, 1)
+    if mc := lines["midheaven"]; len(mc) != 1 || mc[0][0][0] != 120 {
+        t.Errorf("midheaven %v, want one segment at 120", mc)
+    }
+    for angle, segs := range lines {
+        for _, seg := range segs {
+            for i := 1; i < len(seg); i++ {
+                if d := math.Abs(seg[i][0] - seg[i-1][0]); d > 30 {
+                    t.Errorf("%s jumps %.1f° inside a segment at lat %.0f", angle, d, seg[i][1])
+                }
+            }
+        }
+    }
+
+    desc := lines["descendant"]
+    if len(desc) != 2 {
+        t.Fatalf("descendant in %d segments, want 2", len(desc))
+    }
+    a, b := desc[0][len(desc[0])-1], desc[1][0]
+    if a[0] < 170 || b[0] > -170 || b[1]-a[1] != 1 {
+        t.Errorf("descendant split between %v and %v, want neighbours either side of 180°", a, b)
+    }
+
+    // A city on the far side of the antimeridian is still next to the line
+    city := [2]float64{-179.9, a[1] + 0.5}
+    if _, km := nearestOnLine(desc, city); km > math.Min(haversineKm(city, a), haversineKm(city, b)) || km > 100 {
+        t.Errorf("nearest descendant %.1f km from %v", km, city)
+    }
+}
+
+func TestNearestOnLineAcrossAntimeridian(t *testing.T) {
+    line := [][][2]float64{{{179.9, -10}, {179.9, 0}, {179.9, 10}}}
+    for _, c := range []struct {
+        city [2]float64
+        want float64 // km
+    }{
+        {[2]float64{179.9, 5}, 0},
+        {[2]float64{-179.9, 0}, 0.2 * math.Pi / 180 * earthRadiusKm},
+        {[2]float64{-179.9, 5}, 0.2 * math.Pi / 180 * earthRadiusKm * math.Cos(5*math.Pi/180)},
+        {[2]float64{179.9, 20}, 10 * math.Pi / 180 * earthRadiusKm},
+    } {
+        if _, km := nearestOnLine(line, c.city); math.Abs(km-c.want) > 0.5 {
+            t.Errorf("%v: %.2f km from the line, want %.2f", c.city, km, c.want)
+        }
+    }
+}
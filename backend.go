@@ -0,0 +1,178 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "log/slog"
+    "math"
+    "sync"
+)
+
+// ===== Ephemeris backends =====
+
+// Ephemeris is the calculation backend behind every position, house and
+// sidereal time. The methods mirror the Swiss Ephemeris calls the server
+// makes; callers hold withEphemeris, so a backend can keep the sidereal mode
+// and observer as plain state.
+type Ephemeris interface {
+    Name() string
+    JulDay(year, month, day int, hour float64) float64 // Gregorian calendar, UT
+    CalcUt(jd float64, body, flags int, xx []float64) error
+    HousesEx(jd float64, flags int, lat, lon float64, hsys int, cusps, ascmc []float64) error
+    HousesArmc(armc, lat, eps float64, hsys int, cusps, ascmc []float64) error
+    Sidtime(jd float64) float64 // Greenwich apparent sidereal time, hours
+    AyanamsaUt(jd float64) (float64, error)
+    SetSidMode(mode int)
+    SetTopo(lon, lat, alt float64)
+    Close()
+}
+
+// eph is the backend in use, chosen at startup. The WebAssembly build keeps
+// this default.
+var eph Ephemeris = &approxEphemeris{}
+
+// ephemerisBackends are the names ephemeris.backend accepts. auto is Swiss
+// Ephemeris files when the binary has cgo and approx otherwise.
+var ephemerisBackends = map[string]bool{
+    "auto": true, "swiss": true, "moshier": true, "jpl": true, "approx": true, "fake": true,
+}
+
+var (
+    errNoCgo  = errors.New("built without cgo")
+    errHouses = errors.New("house calculation failed")
+)
+
+// newEphemeris builds one named backend.
+func newEphemeris(name string, cfg ephemerisConfig) (Ephemeris, error) {
+    switch name {
+    case "swiss":
+        return newSwissEphemeris(cfg.Path)
+    case "moshier":
+        return newMoshierEphemeris(cfg.Path)
+    case "jpl":
+        return newJPLEphemeris(cfg.Path, cfg.JPLFile)
+    case "approx":
+        return newApproxEphemeris()
+    case "fake":
+        return newFakeEphemeris(), nil
+    }
+    return nil, fmt.Errorf("unknown ephemeris backend %q", name)
+}
+
+// openEphemeris returns the configured backend, wrapped in a comparison when
+// ephemeris.compare names a second one. auto falls back to the pure-Go
+// approximation when the binary has no Swiss Ephemeris.
+func openEphemeris(cfg ephemerisConfig) (Ephemeris, error) {
+    var e Ephemeris
+    var err error
+    if cfg.Backend == "auto" {
+        if e, err = newSwissEphemeris(cfg.Path); err != nil {
+            slog.Warn("Swiss Ephemeris unavailable, using the pure-Go approximation", "err", err)
+            if e, err = newApproxEphemeris(); err != nil {
+                return nil, err
+            }
+        }
+    } else if e, err = newEphemeris(cfg.Backend, cfg); err != nil {
+        return nil, err
+    }
+    if cfg.Compare == "" {
+        return e, nil
+    }
+    other, err := newEphemeris(cfg.Compare, cfg)
+    if err != nil {
+        e.Close()
+        return nil, fmt.Errorf("comparison backend: %w", err)
+    }
+    return &comparingEphemeris{Ephemeris: e, other: other, tolerance: cfg.CompareTolerance,
+        worst: map[string]float64{}}, nil
+}
+
+// ephemerisLimits is implemented by backends that can't compute everything
+// Swiss Ephemeris can; the others support every option.
+type ephemerisLimits interface {
+    supportsHouseSystem(hsys byte) bool
+    supportsAyanamsa(mode int) bool
+    supportsTopocentric() bool
+}
+
+// settingsLocker is implemented by backends whose sidereal mode and
+// topocentric observer are shared between callers. withEphemeris holds the
+// lock exclusively while a request changes them and shared otherwise, so
+// tropical geocentric calls run side by side. Other backends keep no such
+// state and are never serialized.
+type settingsLocker interface {
+    lockSettings(exclusive bool) (unlock func())
+}
+
+// lockSettings takes e's settings lock, if it has one.
+func lockSettings(e Ephemeris, exclusive bool) (unlock func()) {
+    if l, ok := e.(settingsLocker); ok {
+        return l.lockSettings(exclusive)
+    }
+    return func() {}
+}
+
+// settingsLock implements settingsLocker.
+type settingsLock struct{ mu sync.RWMutex }
+
+func (l *settingsLock) lockSettings(exclusive bool) func() {
+    if exclusive {
+        l.mu.Lock()
+        return l.mu.Unlock
+    }
+    l.mu.RLock()
+    return l.mu.RUnlock
+}
+
+// checkHouseSystem reports whether e can cast houses in the named system.
+func checkHouseSystem(e Ephemeris, system string) error {
+    if l, ok := e.(ephemerisLimits); ok && !l.supportsHouseSystem(houseSystems[system]) {
+        return fmt.Errorf("house system %q is not available in the %s ephemeris", system, e.Name())
+    }
+    return nil
+}
+
+// checkCalcOptions reports whether e can answer a request with options o.
+func checkCalcOptions(e Ephemeris, o calcOptions) error {
+    l, ok := e.(ephemerisLimits)
+    if !ok {
+        return nil
+    }
+    if o.sidereal() && !l.supportsAyanamsa(ayanamsas[o.Ayanamsa]) {
+        return fmt.Errorf("ayanamsa %q is not available in the %s ephemeris", o.Ayanamsa, e.Name())
+    }
+    if o.Topocentric && !l.supportsTopocentric() {
+        return fmt.Errorf("topocentric positions are not available in the %s ephemeris", e.Name())
+    }
+    return nil
+}
+
+// checkEphemerisDefaults fails when the backend can't serve the configured
+// defaults, which would otherwise fail every reading.
+func checkEphemerisDefaults(e Ephemeris, cfg ephemerisConfig) error {
+    if err := checkHouseSystem(e, cfg.HouseSystem); err != nil {
+        return fmt.Errorf("ephemeris.house_system: %w", err)
+    }
+    // Sidereal requests fall back to the configured ayanamsa
+    o := calcOptions{Zodiac: "sidereal", Ayanamsa: cfg.Ayanamsa}
+    if err := checkCalcOptions(e, o); err != nil {
+        return fmt.Errorf("ephemeris.ayanamsa: %w", err)
+    }
+    return nil
+}
+
+// gregorianJulDay is the Julian Day of a Gregorian calendar date (Meeus,
+// Astronomical Algorithms ch. 7), for backends without swe.Julday.
+func gregorianJulDay(year, month, day int, hour float64) float64 {
+    if month <= 2 {
+        year -= 1
+        month += 12
+    }
+    A := year / 100
+    B := 2 - A + A/4
+
+    return math.Floor(365.25*float64(year+4716)) +
+        math.Floor(30.6001*float64(month+1)) +
+        float64(day) + float64(B) - 1524.5 +
+        hour/24.0
+}
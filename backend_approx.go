@@ -0,0 +1,589 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "strings"
+)
+
+// ===== Pure-Go approximate ephemeris =====
+
+// approxEphemeris needs neither cgo nor data files: Chiron comes from the
+// embedded Chebyshev table (1800-2200), the Moon from the main terms of
+// Meeus's lunar theory, the Sun and planets from JPL's approximate Keplerian
+// elements with corrections fitted to Swiss Ephemeris, and houses from the
+// standard spherical formulas. See the README for its accuracy.
+//
+// The fitted tables live in approx_tables.go; regenerating them prints the
+// worst residual of each fit.
+//
+//go:generate go run ./tools/approxfit -o approx_tables.go
+type approxEphemeris struct {
+    settingsLock
+    sidMode int
+}
+
+// newApproxEphemeris fails when the embedded Chiron table doesn't load: the
+// pure-Go ephemeris has no Chiron of its own to fall back on.
+func newApproxEphemeris() (Ephemeris, error) {
+    if chironTableErr != nil {
+        return nil, fmt.Errorf("pure-Go ephemeris: %w", chironTableErr)
+    }
+    return &approxEphemeris{}, nil
+}
+
+func (*approxEphemeris) Name() string { return "approx" }
+func (*approxEphemeris) Close()       {}
+
+func (*approxEphemeris) JulDay(year, month, day int, hour float64) float64 {
+    return gregorianJulDay(year, month, day, hour)
+}
+
+// Topocentric positions are refused rather than silently made geocentric.
+func (*approxEphemeris) SetTopo(lon, lat, alt float64) {}
+
+func (a *approxEphemeris) SetSidMode(mode int) { a.sidMode = mode }
+
+func (*approxEphemeris) supportsHouseSystem(hsys byte) bool {
+    return strings.IndexByte("WEAORP", hsys) >= 0
+}
+
+func (*approxEphemeris) supportsAyanamsa(mode int) bool {
+    _, ok := approxAyanamsas[mode]
+    return ok
+}
+
+func (*approxEphemeris) supportsTopocentric() bool { return false }
+
+const (
+    deg2rad    = math.Pi / 180
+    arcsec     = 1.0 / 3600
+    aberration = 20.49552 * arcsec
+    lightDay   = 0.0057755183 // days per AU
+    speedStep  = 0.005        // days, for numerical speeds
+)
+
+var errApproxRange = errors.New("the pure-Go ephemeris covers Chiron from 1800 to 2200 only")
+
+// deltaT returns TT-UT in seconds (Espenak and Meeus polynomials).
+func deltaT(jd float64) float64 {
+    y := 2000 + (jd-2451545)/365.25
+    switch {
+    case y < 1800:
+        u := (y - 1820) / 100
+        return -20 + 32*u*u
+    case y < 1860:
+        t := y - 1800
+        return 13.72 - 0.332447*t + 0.0068612*t*t + 0.0041116*t*t*t - 0.00037436*math.Pow(t, 4) +
+            0.0000121272*math.Pow(t, 5) - 0.0000001699*math.Pow(t, 6) + 0.000000000875*math.Pow(t, 7)
+    case y < 1900:
+        t := y - 1860
+        return 7.62 + 0.5737*t - 0.251754*t*t + 0.01680668*t*t*t - 0.0004473624*math.Pow(t, 4) + math.Pow(t, 5)/233174
+    case y < 1920:
+        t := y - 1900
+        return -2.79 + 1.494119*t - 0.0598939*t*t + 0.0061966*t*t*t - 0.000197*math.Pow(t, 4)
+    case y < 1941:
+        t := y - 1920
+        return 21.20 + 0.84493*t - 0.076100*t*t + 0.0020936*t*t*t
+    case y < 1961:
+        t := y - 1950
+        return 29.07 + 0.407*t - t*t/233 + t*t*t/2547
+    case y < 1986:
+        t := y - 1975
+        return 45.45 + 1.067*t - t*t/260 - t*t*t/718
+    case y < 2005:
+        t := y - 2000
+        return 63.86 + 0.3345*t - 0.060374*t*t + 0.0017275*t*t*t + 0.000651814*math.Pow(t, 4) + 0.00002373599*math.Pow(t, 5)
+    case y < 2050:
+        t := y - 2000
+        return 62.92 + 0.32217*t + 0.005589*t*t
+    case y < 2150:
+        u := (y - 1820) / 100
+        return -20 + 32*u*u - 0.5628*(2150-y)
+    }
+    u := (y - 1820) / 100
+    return -20 + 32*u*u
+}
+
+// nutation returns the nutation in longitude and obliquity, degrees, to
+// about 0.5".
+func nutation(t float64) (dpsi, deps float64) {
+    om := (125.04452 - 1934.136261*t) * deg2rad
+    l := (280.4665 + 36000.7698*t) * deg2rad
+    lm := (218.3165 + 481267.8813*t) * deg2rad
+    dpsi = -17.20*math.Sin(om) - 1.32*math.Sin(2*l) - 0.23*math.Sin(2*lm) + 0.21*math.Sin(2*om)
+    deps = 9.20*math.Cos(om) + 0.57*math.Cos(2*l) + 0.10*math.Cos(2*lm) - 0.09*math.Cos(2*om)
+    return dpsi * arcsec, deps * arcsec
+}
+
+func meanObliquity(t float64) float64 {
+    return 23.439291111 + (-46.8150*t-0.00059*t*t+0.001813*t*t*t)*arcsec
+}
+
+func centuries(jd float64) float64 { return (jd - 2451545) / 36525 }
+
+func normDeg(x float64) float64 {
+    x = math.Mod(x, 360)
+    if x < 0 {
+        x += 360
+    }
+    return x
+}
+
+const earthBary = -100 // key of the Earth-Moon barycentre in keplerElements
+
+// keplerFit corrects a planet's Keplerian position: Chebyshev series in time
+// plus periodic terms, longitude and latitude in arcseconds, distance in
+// micro-AU.
+type keplerFit struct {
+    lon, lat, dist [9]float64
+    terms          []keplerTerm
+}
+
+// keplerTerm is one periodic correction. Its argument is self times the
+// planet's mean anomaly plus mult times body's; each series holds the sine
+// and cosine amplitudes times the Chebyshev polynomials T0, T1 and T2 of the
+// time.
+type keplerTerm struct {
+    self, body, mult int
+    lon, lat, dist   [6]float64
+}
+
+// meanAnomaly is a body's mean anomaly in radians at TT centuries t.
+func meanAnomaly(body int, t float64) float64 {
+    el := keplerElements[body]
+    return (el[3] + el[9]*t - el[4] - el[10]*t) * deg2rad
+}
+
+// correction evaluates a planet's keplerFit. Outside the years it was fitted
+// to, the slow part is held at its value at the nearer end.
+func correction(body int, t float64) (dlon, dlat, ddist float64) {
+    f, ok := keplerFits[body]
+    if !ok {
+        return 0, 0, 0
+    }
+    x := (2*t - keplerFitStart - keplerFitEnd) / (keplerFitEnd - keplerFitStart)
+    x = math.Max(-1, math.Min(1, x))
+    var tk [9]float64
+    tk[0], tk[1] = 1, x
+    for k := 2; k < len(tk); k++ {
+        tk[k] = 2*x*tk[k-1] - tk[k-2]
+    }
+    for k, v := range tk {
+        dlon += f.lon[k] * v
+        dlat += f.lat[k] * v
+        ddist += f.dist[k] * v
+    }
+    m := meanAnomaly(body, t)
+    for _, term := range f.terms {
+        arg := float64(term.self) * m
+        if term.body != 0 {
+            arg += float64(term.mult) * meanAnomaly(term.body, t)
+        }
+        s, c := math.Sincos(arg)
+        for k := 0; k < 3; k++ {
+            dlon += tk[k] * (term.lon[2*k]*s + term.lon[2*k+1]*c)
+            dlat += tk[k] * (term.lat[2*k]*s + term.lat[2*k+1]*c)
+            ddist += tk[k] * (term.dist[2*k]*s + term.dist[2*k+1]*c)
+        }
+    }
+    return dlon * arcsec, dlat * arcsec, ddist * 1e-6
+}
+
+// heliocentric returns a body's heliocentric rectangular position, J2000
+// ecliptic, at TT centuries t.
+func heliocentric(body int, t float64) [3]float64 {
+    el := keplerElements[body]
+    a := el[0] + el[6]*t
+    e := el[1] + el[7]*t
+    i := (el[2] + el[8]*t) * deg2rad
+    l := el[3] + el[9]*t
+    peri := el[4] + el[10]*t
+    node := el[5] + el[11]*t
+    w := (peri - node) * deg2rad
+    m := normDeg(l-peri) * deg2rad
+    ea := m + e*math.Sin(m)
+    for k := 0; k < 10; k++ {
+        ea -= (ea - e*math.Sin(ea) - m) / (1 - e*math.Cos(ea))
+    }
+    xp := a * (math.Cos(ea) - e)
+    yp := a * math.Sqrt(1-e*e) * math.Sin(ea)
+    n := node * deg2rad
+    cw, sw, cn, sn, ci, si := math.Cos(w), math.Sin(w), math.Cos(n), math.Sin(n), math.Cos(i), math.Sin(i)
+    lon, lat, dist := toSpherical([3]float64{
+        (cw*cn-sw*sn*ci)*xp + (-sw*cn-cw*sn*ci)*yp,
+        (cw*sn+sw*cn*ci)*xp + (-sw*sn+cw*cn*ci)*yp,
+        sw*si*xp + cw*si*yp,
+    })
+    dlon, dlat, ddist := correction(body, t)
+    return toRect(lon+dlon, lat+dlat, dist+ddist)
+}
+
+// earth is the Earth's heliocentric position, J2000 ecliptic: the barycentre
+// less the Moon's share of their separation.
+func earth(t float64) [3]float64 {
+    const moonShare = 1 / (1 + 81.30057) // Moon's mass over the Earth's and Moon's
+    emb := heliocentric(earthBary, t)
+    lon, lat, dist := moon(t)
+    m := toRect(lon-5029.0966*t*arcsec, lat, dist) // back to the J2000 equinox, roughly
+    return [3]float64{emb[0] - moonShare*m[0], emb[1] - moonShare*m[1], emb[2] - moonShare*m[2]}
+}
+
+func toSpherical(v [3]float64) (lon, lat, dist float64) {
+    dist = math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+    return normDeg(math.Atan2(v[1], v[0]) / deg2rad), math.Asin(v[2]/dist) / deg2rad, dist
+}
+
+func toRect(lon, lat, dist float64) [3]float64 {
+    l, b := lon*deg2rad, lat*deg2rad
+    return [3]float64{dist * math.Cos(b) * math.Cos(l), dist * math.Cos(b) * math.Sin(l), dist * math.Sin(b)}
+}
+
+// precessEcliptic moves J2000 ecliptic coordinates to the mean ecliptic and
+// equinox of date (Meeus 21.5).
+func precessEcliptic(lon, lat, t float64) (float64, float64) {
+    eta := (47.0029*t - 0.03302*t*t + 0.00006*t*t*t) * arcsec * deg2rad
+    pi := 174.876384*deg2rad + (-869.8089*t+0.03536*t*t)*arcsec*deg2rad
+    p := (5029.0966*t + 1.11113*t*t - 0.000006*t*t*t) * arcsec
+    l, b := lon*deg2rad, lat*deg2rad
+    a := math.Cos(eta)*math.Cos(b)*math.Sin(pi-l) - math.Sin(eta)*math.Sin(b)
+    bb := math.Cos(b) * math.Cos(pi-l)
+    c := math.Cos(eta)*math.Sin(b) + math.Sin(eta)*math.Cos(b)*math.Sin(pi-l)
+    return normDeg(p + (pi-math.Atan2(a, bb))/deg2rad), math.Asin(c) / deg2rad
+}
+
+// moonTerms are the largest periodic terms of Meeus chapter 47: multiples
+// of D, M, M', F, then longitude and distance coefficients (1e-6 deg, 1e-3 km).
+var moonTerms = [][6]float64{
+    {0, 0, 1, 0, 6288774, -20905355},
+    {2, 0, -1, 0, 1274027, -3699111},
+    {2, 0, 0, 0, 658314, -2955968},
+    {0, 0, 2, 0, 213618, -569925},
+    {0, 1, 0, 0, -185116, 48888},
+    {0, 0, 0, 2, -114332, -3149},
+    {2, 0, -2, 0, 58793, 246158},
+    {2, -1, -1, 0, 57066, -152138},
+    {2, 0, 1, 0, 53322, -170733},
+    {2, -1, 0, 0, 45758, -204586},
+    {0, 1, -1, 0, -40923, -129620},
+    {1, 0, 0, 0, -34720, 108743},
+    {0, 1, 1, 0, -30383, 104755},
+    {2, 0, 0, -2, 15327, 10321},
+    {0, 0, 1, 2, -12528, 0},
+    {0, 0, 1, -2, 10980, 79661},
+    {4, 0, -1, 0, 10675, -34782},
+    {0, 0, 3, 0, 10034, -23210},
+    {4, 0, -2, 0, 8548, -21636},
+    {2, 1, -1, 0, -7888, 24208},
+    {2, 1, 0, 0, -6766, 30824},
+    {1, 0, -1, 0, -5163, -8379},
+    {1, 1, 0, 0, 4987, -16675},
+    {2, -1, 1, 0, 4036, -12831},
+    {2, 0, 2, 0, 3994, -10445},
+    {4, 0, 0, 0, 3861, -11650},
+    {2, 0, -3, 0, 3665, 14403},
+    {0, 1, -2, 0, -2689, -7003},
+    {2, 0, -1, 2, -2602, 0},
+    {2, -1, -2, 0, 2390, 10056},
+    {1, 0, 1, 0, -2348, 6322},
+    {2, -2, 0, 0, 2236, -9884},
+}
+
+// moonLatTerms: multiples of D, M, M', F, then the latitude coefficient.
+var moonLatTerms = [][5]float64{
+    {0, 0, 0, 1, 5128122},
+    {0, 0, 1, 1, 280602},
+    {0, 0, 1, -1, 277693},
+    {2, 0, 0, -1, 173237},
+    {2, 0, -1, 1, 55413},
+    {2, 0, -1, -1, 46271},
+    {2, 0, 0, 1, 32573},
+    {0, 0, 2, 1, 17198},
+    {2, 0, 1, -1, 9266},
+    {0, 0, 2, -1, 8822},
+    {2, -1, 0, -1, 8216},
+    {2, 0, -2, -1, 4324},
+    {2, 0, 1, 1, 4200},
+    {2, 1, 0, -1, -3359},
+}
+
+// moon returns the geocentric Moon, mean equinox of date, at TT centuries t.
+func moon(t float64) (lon, lat, dist float64) {
+    lp := 218.3164477 + 481267.88123421*t - 0.0015786*t*t + t*t*t/538841 - t*t*t*t/65194000
+    d := (297.8501921 + 445267.1114034*t - 0.0018819*t*t + t*t*t/545868 - t*t*t*t/113065000) * deg2rad
+    m := (357.5291092 + 35999.0502909*t - 0.0001536*t*t + t*t*t/24490000) * deg2rad
+    mp := (134.9633964 + 477198.8675055*t + 0.0087414*t*t + t*t*t/69699 - t*t*t*t/14712000) * deg2rad
+    f := (93.2720950 + 483202.0175233*t - 0.0036539*t*t - t*t*t/3526000 + t*t*t*t/863310000) * deg2rad
+    a1 := (119.75 + 131.849*t) * deg2rad
+    a2 := (53.09 + 479264.290*t) * deg2rad
+    a3 := (313.45 + 481266.484*t) * deg2rad
+    e := 1 - 0.002516*t - 0.0000074*t*t
+    ecc := func(mMult float64) float64 { return math.Pow(e, math.Abs(mMult)) }
+
+    var sl, sr, sb float64
+    for _, k := range moonTerms {
+        arg := k[0]*d + k[1]*m + k[2]*mp + k[3]*f
+        sl += k[4] * ecc(k[1]) * math.Sin(arg)
+        sr += k[5] * ecc(k[1]) * math.Cos(arg)
+    }
+    for _, k := range moonLatTerms {
+        sb += k[4] * ecc(k[1]) * math.Sin(k[0]*d+k[1]*m+k[2]*mp+k[3]*f)
+    }
+    lpr := lp * deg2rad
+    sl += 3958*math.Sin(a1) + 1962*math.Sin(lpr-f) + 318*math.Sin(a2)
+    sb += -2235*math.Sin(lpr) + 382*math.Sin(a3) + 175*math.Sin(a1-f) + 175*math.Sin(a1+f) +
+        127*math.Sin(lpr-mp) - 115*math.Sin(lpr+mp)
+    return normDeg(lp + sl/1e6), sb / 1e6, (385000.56 + sr/1000) / 149597870.7
+}
+
+// earthOfDate is the Earth's heliocentric position, ecliptic of date.
+func earthOfDate(t float64) [3]float64 {
+    l, b, r := toSpherical(earth(t))
+    l, b = precessEcliptic(l, b, t)
+    return toRect(l, b, r)
+}
+
+// position returns apparent ecliptic coordinates of date, tropical, before
+// any sidereal or equatorial conversion.
+func (a *approxEphemeris) position(jd float64, body, flags int) (lon, lat, dist float64, err error) {
+    t := centuries(jd + deltaT(jd)/86400)
+    helio := flags&SEFLG_HELCTR != 0
+    dpsi, _ := nutation(t)
+
+    switch {
+    case body == SE_CHIRON:
+        if chironTableErr != nil {
+            return 0, 0, 0, chironTableErr
+        }
+        var ok bool
+        if lon, lat, dist, _, ok = chironTable.at(jd); !ok {
+            return 0, 0, 0, errApproxRange
+        }
+        if helio {
+            g, e := toRect(lon, lat, dist), earthOfDate(t)
+            lon, lat, dist = toSpherical([3]float64{g[0] + e[0], g[1] + e[1], g[2] + e[2]})
+        }
+        return lon, lat, dist, nil
+    case body == SE_MOON:
+        if helio {
+            return 0, 0, 0, errors.New("heliocentric Moon is not available in the pure-Go ephemeris")
+        }
+        lon, lat, dist = moon(t)
+        return normDeg(lon + dpsi), lat, dist, nil
+    case body == SE_SUN:
+        if helio {
+            return 0, 0, 0, nil
+        }
+        l, b, r := toSpherical(earth(t))
+        l, b = precessEcliptic(normDeg(l+180), -b, t)
+        return normDeg(l + dpsi - aberration/r), b, r, nil
+    }
+    if _, ok := keplerElements[body]; !ok {
+        return 0, 0, 0, fmt.Errorf("body %d is not available in the pure-Go ephemeris", body)
+    }
+    if helio {
+        _, _, r := toSpherical(heliocentric(body, t))
+        l, b, r := toSpherical(heliocentric(body, t-r*lightDay/36525)) // light time from the Sun
+        l, b = precessEcliptic(l, b, t)
+        return normDeg(l + dpsi), b, r, nil
+    }
+    e := earth(t)
+    var g [3]float64
+    tau := 0.0
+    for k := 0; k < 2; k++ { // light time
+        p := heliocentric(body, t-tau/36525)
+        g = [3]float64{p[0] - e[0], p[1] - e[1], p[2] - e[2]}
+        _, _, dist = toSpherical(g)
+        tau = dist * lightDay
+    }
+    l, b, r := toSpherical(g)
+    l, b = precessEcliptic(l, b, t)
+    sun, _, _ := toSpherical(e)
+    sun, _ = precessEcliptic(normDeg(sun+180), 0, t)
+    l += -aberration * math.Cos((sun-l)*deg2rad) / math.Cos(b*deg2rad)
+    b += -aberration * math.Sin((sun-l)*deg2rad) * math.Sin(b*deg2rad)
+    return normDeg(l + dpsi), b, r, nil
+}
+
+// coordinates applies the sidereal or equatorial conversion to position.
+func (a *approxEphemeris) coordinates(jd float64, body, flags int) ([3]float64, error) {
+    if flags&SEFLG_TOPOCTR != 0 {
+        return [3]float64{}, errors.New("topocentric positions need the Swiss Ephemeris build")
+    }
+    lon, lat, dist, err := a.position(jd, body, flags)
+    if err != nil {
+        return [3]float64{}, err
+    }
+    switch {
+    case flags&SEFLG_EQUATORIAL != 0:
+        t := centuries(jd)
+        _, deps := nutation(t)
+        e := (meanObliquity(t) + deps) * deg2rad
+        l, b := lon*deg2rad, lat*deg2rad
+        ra := math.Atan2(math.Sin(l)*math.Cos(e)-math.Tan(b)*math.Sin(e), math.Cos(l))
+        dec := math.Asin(math.Sin(b)*math.Cos(e) + math.Cos(b)*math.Sin(e)*math.Sin(l))
+        return [3]float64{normDeg(ra / deg2rad), dec / deg2rad, dist}, nil
+    case flags&SEFLG_SIDEREAL != 0:
+        ayan, err := a.AyanamsaUt(jd)
+        if err != nil {
+            return [3]float64{}, err
+        }
+        return [3]float64{normDeg(lon - ayan), lat, dist}, nil
+    }
+    return [3]float64{lon, lat, dist}, nil
+}
+
+func (a *approxEphemeris) CalcUt(jd float64, body, flags int, xx []float64) error {
+    if body == SE_ECL_NUT {
+        t := centuries(jd)
+        dpsi, deps := nutation(t)
+        eps := meanObliquity(t)
+        copy(xx, []float64{eps + deps, eps, dpsi, deps, 0, 0})
+        return nil
+    }
+    p, err := a.coordinates(jd, body, flags)
+    if err != nil {
+        return err
+    }
+    copy(xx, p[:])
+    xx[3], xx[4], xx[5] = 0, 0, 0
+    if flags&SEFLG_SPEED != 0 {
+        // Central difference, one-sided at the edge of the Chiron table
+        before, err1 := a.coordinates(jd-speedStep, body, flags)
+        after, err2 := a.coordinates(jd+speedStep, body, flags)
+        span := 2 * speedStep
+        if err1 != nil {
+            before, span = p, speedStep
+        }
+        if err2 != nil {
+            after, span = p, speedStep
+        }
+        if err1 == nil || err2 == nil {
+            xx[3] = math.Remainder(after[0]-before[0], 360) / span
+            xx[4] = (after[1] - before[1]) / span
+            xx[5] = (after[2] - before[2]) / span
+        }
+    }
+    return nil
+}
+
+func (a *approxEphemeris) Sidtime(jd float64) float64 {
+    t := centuries(jd)
+    gmst := 280.46061837 + 360.98564736629*(jd-2451545) + 0.000387933*t*t - t*t*t/38710000
+    dpsi, deps := nutation(t)
+    gast := gmst + dpsi*math.Cos((meanObliquity(t)+deps)*deg2rad)
+    return normDeg(gast) / 15
+}
+
+func (a *approxEphemeris) AyanamsaUt(jd float64) (float64, error) {
+    c, ok := approxAyanamsas[a.sidMode]
+    if !ok {
+        return 0, errors.New("this ayanamsa needs the Swiss Ephemeris build")
+    }
+    t := centuries(jd)
+    dpsi, _ := nutation(t)
+    sun := (280.46646 + 36000.76983*t) * deg2rad
+    return normDeg(c[0] + c[1]*t + c[2]*t*t + c[3]*dpsi + c[4]*math.Cos(sun) + c[5]*math.Sin(sun)), nil
+}
+
+func (a *approxEphemeris) HousesEx(jd float64, flags int, lat, lon float64, hsys int, cusps, ascmc []float64) error {
+    t := centuries(jd)
+    _, deps := nutation(t)
+    armc := normDeg(a.Sidtime(jd)*15 + lon)
+    shift := 0.0
+    if flags&SEFLG_SIDEREAL != 0 {
+        var err error
+        if shift, err = a.AyanamsaUt(jd); err != nil {
+            return err
+        }
+    }
+    return approxHouses(armc, lat, meanObliquity(t)+deps, hsys, shift, cusps, ascmc)
+}
+
+func (a *approxEphemeris) HousesArmc(armc, lat, eps float64, hsys int, cusps, ascmc []float64) error {
+    return approxHouses(armc, lat, eps, hsys, 0, cusps, ascmc)
+}
+
+// cuspOnCircle is the ecliptic longitude where the house circle with the
+// given pole meets the ecliptic at right ascension ra (degrees).
+func cuspOnCircle(ra, tanPole, eps float64) float64 {
+    r, e := ra*deg2rad, eps*deg2rad
+    return normDeg(math.Atan2(math.Sin(r), math.Cos(r)*math.Cos(e)-tanPole*math.Sin(e)) / deg2rad)
+}
+
+// approxHouses supports whole sign, equal, Porphyry, Placidus and
+// Regiomontanus; shift (the ayanamsa) is subtracted from every result.
+func approxHouses(armc, lat, eps float64, hsys int, shift float64, cusps, ascmc []float64) error {
+    tanLat := math.Tan(lat * deg2rad)
+    mc := cuspOnCircle(armc, 0, eps)
+    asc := cuspOnCircle(armc+90, tanLat, eps)
+    ascmc[0], ascmc[1], ascmc[2] = normDeg(asc-shift), normDeg(mc-shift), armc
+    for i := 3; i < len(ascmc); i++ {
+        ascmc[i] = 0
+    }
+    asc, mc = ascmc[0], ascmc[1]
+
+    var c [13]float64
+    switch hsys {
+    case 'W':
+        for i := 1; i <= 12; i++ {
+            c[i] = normDeg(math.Floor(asc/30)*30 + float64(i-1)*30)
+        }
+    case 'E', 'A':
+        for i := 1; i <= 12; i++ {
+            c[i] = normDeg(asc + float64(i-1)*30)
+        }
+    case 'O':
+        q := normDeg(asc - mc)
+        c[10], c[11], c[12], c[1] = mc, normDeg(mc+q/3), normDeg(mc+2*q/3), asc
+        c[2], c[3] = normDeg(asc+(180-q)/3), normDeg(asc+2*(180-q)/3)
+    case 'R':
+        s30, s60 := math.Sin(30*deg2rad), math.Sin(60*deg2rad)
+        c[10], c[1] = mc, asc
+        c[11] = normDeg(cuspOnCircle(armc+30, tanLat*s30, eps) - shift)
+        c[12] = normDeg(cuspOnCircle(armc+60, tanLat*s60, eps) - shift)
+        c[2] = normDeg(cuspOnCircle(armc+120, tanLat*s60, eps) - shift)
+        c[3] = normDeg(cuspOnCircle(armc+150, tanLat*s30, eps) - shift)
+    case 'P':
+        if math.Abs(lat) >= 90-eps {
+            return errHouses
+        }
+        c[10], c[1] = mc, asc
+        // Each cusp is where the ecliptic point's own semi-arc is trisected
+        for _, h := range []struct {
+            house     int
+            frac, off float64
+        }{{11, 1.0 / 3, 30}, {12, 2.0 / 3, 60}, {2, -2.0 / 3, 120}, {3, -1.0 / 3, 150}} {
+            ra := armc + h.off
+            for k := 0; k < 50; k++ {
+                lon := cuspOnCircle(ra, 0, eps)
+                dec := math.Asin(math.Sin(eps*deg2rad) * math.Sin(lon*deg2rad))
+                x := tanLat * math.Tan(dec)
+                if math.Abs(x) > 1 {
+                    return errHouses
+                }
+                dsa := 90 + math.Asin(x)/deg2rad
+                next := armc + h.frac*dsa
+                if h.frac < 0 {
+                    next = armc + 180 + h.frac*(180-dsa)
+                }
+                if math.Abs(math.Remainder(next-ra, 360)) < 1e-9 {
+                    break
+                }
+                ra = next
+            }
+            c[h.house] = normDeg(cuspOnCircle(ra, 0, eps) - shift)
+        }
+    default:
+        return fmt.Errorf("house system %q is not available in the pure-Go ephemeris", rune(hsys))
+    }
+    if hsys != 'W' && hsys != 'E' && hsys != 'A' {
+        // Quadrant systems: the other six cusps are opposite these
+        for _, i := range []int{1, 2, 3, 10, 11, 12} {
+            c[(i+5)%12+1] = normDeg(c[i] + 180)
+        }
+    }
+    copy(cusps, c[:])
+    return nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+    "log/slog"
+    "math"
+    "sync"
+)
+
+// ===== Backend comparison =====
+
+// comparingEphemeris answers from its primary backend and repeats every call
+// on a second one, recording how far apart the two are in
+// chiron_ephemeris_discrepancy_arcseconds and logging each new worst case
+// beyond the tolerance. It doubles the cost of every call, so it is meant for checking a
+// backend rather than for production traffic.
+type comparingEphemeris struct {
+    Ephemeris // primary, whose answers are returned
+    other     Ephemeris
+    tolerance float64 // arcseconds
+
+    mu    sync.Mutex
+    worst map[string]float64 // largest discrepancy logged, by call
+}
+
+func (c *comparingEphemeris) Name() string {
+    return c.Ephemeris.Name() + " vs " + c.other.Name()
+}
+
+// arcsecDiff is the angular distance between two longitudes in arcseconds.
+func arcsecDiff(a, b float64) float64 {
+    return math.Abs(math.Remainder(a-b, 360)) * 3600
+}
+
+func (c *comparingEphemeris) report(call string, jd, diff float64, attrs ...any) {
+    ephemerisDiscrepancy.observe(diff, call, c.other.Name())
+    if diff <= c.tolerance {
+        return
+    }
+    // Scans repeat the same disagreement thousands of times; log only news
+    c.mu.Lock()
+    worse := diff > c.worst[call]
+    if worse {
+        c.worst[call] = diff
+    }
+    c.mu.Unlock()
+    if worse {
+        slog.Warn("ephemeris backends disagree", append([]any{
+            "call", call, "jd", jd, "arcsec", math.Round(diff*100) / 100,
+            "primary", c.Ephemeris.Name(), "other", c.other.Name(),
+        }, attrs...)...)
+    }
+}
+
+// failed notes a call only the primary could answer, such as a topocentric
+// position from the pure-Go backend.
+func (c *comparingEphemeris) failed(call string, err error) {
+    ephemerisErrors.inc("compare_" + call)
+    slog.Debug("comparison backend failed", "call", call, "backend", c.other.Name(), "err", err)
+}
+
+func (c *comparingEphemeris) CalcUt(jd float64, body, flags int, xx []float64) error {
+    if err := c.Ephemeris.CalcUt(jd, body, flags, xx); err != nil {
+        return err
+    }
+    yy := make([]float64, len(xx))
+    if err := c.other.CalcUt(jd, body, flags, yy); err != nil {
+        c.failed("calc", err)
+        return nil
+    }
+    // Longitude (or right ascension) and latitude; distance is not an angle
+    diff := math.Max(arcsecDiff(xx[0], yy[0]), math.Abs(xx[1]-yy[1])*3600)
+    c.report("calc", jd, diff, "body", body, "flags", flags)
+    return nil
+}
+
+// compareHouses reports the largest difference among the cusps, Ascendant
+// and MC.
+func (c *comparingEphemeris) compareHouses(call string, jd float64, hsys int, cusps, ascmc, cusps2, ascmc2 []float64) {
+    diff := math.Max(arcsecDiff(ascmc[0], ascmc2[0]), arcsecDiff(ascmc[1], ascmc2[1]))
+    for i := 1; i <= 12 && i < len(cusps); i++ {
+        diff = math.Max(diff, arcsecDiff(cusps[i], cusps2[i]))
+    }
+    c.report(call, jd, diff, "house_system", string(rune(hsys)))
+}
+
+func (c *comparingEphemeris) HousesEx(jd float64, flags int, lat, lon float64, hsys int, cusps, ascmc []float64) error {
+    if err := c.Ephemeris.HousesEx(jd, flags, lat, lon, hsys, cusps, ascmc); err != nil {
+        return err
+    }
+    cusps2, ascmc2 := make([]float64, len(cusps)), make([]float64, len(ascmc))
+    if err := c.other.HousesEx(jd, flags, lat, lon, hsys, cusps2, ascmc2); err != nil {
+        c.failed("houses", err)
+        return nil
+    }
+    c.compareHouses("houses", jd, hsys, cusps, ascmc, cusps2, ascmc2)
+    return nil
+}
+
+func (c *comparingEphemeris) HousesArmc(armc, lat, eps float64, hsys int, cusps, ascmc []float64) error {
+    if err := c.Ephemeris.HousesArmc(armc, lat, eps, hsys, cusps, ascmc); err != nil {
+        return err
+    }
+    cusps2, ascmc2 := make([]float64, len(cusps)), make([]float64, len(ascmc))
+    if err := c.other.HousesArmc(armc, lat, eps, hsys, cusps2, ascmc2); err != nil {
+        c.failed("houses_armc", err)
+        return nil
+    }
+    c.compareHouses("houses_armc", 0, hsys, cusps, ascmc, cusps2, ascmc2)
+    return nil
+}
+
+func (c *comparingEphemeris) Sidtime(jd float64) float64 {
+    st := c.Ephemeris.Sidtime(jd)
+    c.report("sidtime", jd, arcsecDiff(st*15, c.other.Sidtime(jd)*15))
+    return st
+}
+
+func (c *comparingEphemeris) AyanamsaUt(jd float64) (float64, error) {
+    a, err := c.Ephemeris.AyanamsaUt(jd)
+    if err != nil {
+        return 0, err
+    }
+    if b, err := c.other.AyanamsaUt(jd); err != nil {
+        c.failed("ayanamsa", err)
+    } else {
+        c.report("ayanamsa", jd, arcsecDiff(a, b))
+    }
+    return a, nil
+}
+
+// The primary's limits are the comparison's: only its answers are returned.
+
+func (c *comparingEphemeris) supportsHouseSystem(hsys byte) bool {
+    l, ok := c.Ephemeris.(ephemerisLimits)
+    return !ok || l.supportsHouseSystem(hsys)
+}
+
+func (c *comparingEphemeris) supportsAyanamsa(mode int) bool {
+    l, ok := c.Ephemeris.(ephemerisLimits)
+    return !ok || l.supportsAyanamsa(mode)
+}
+
+func (c *comparingEphemeris) supportsTopocentric() bool {
+    l, ok := c.Ephemeris.(ephemerisLimits)
+    return !ok || l.supportsTopocentric()
+}
+
+// Julian Days are the same calendar arithmetic in every backend, so JulDay
+// goes to the primary alone.
+
+func (c *comparingEphemeris) SetSidMode(mode int) {
+    c.Ephemeris.SetSidMode(mode)
+    c.other.SetSidMode(mode)
+}
+
+func (c *comparingEphemeris) SetTopo(lon, lat, alt float64) {
+    c.Ephemeris.SetTopo(lon, lat, alt)
+    c.other.SetTopo(lon, lat, alt)
+}
+
+func (c *comparingEphemeris) lockSettings(exclusive bool) func() {
+    unlock := lockSettings(c.Ephemeris, exclusive)
+    unlockOther := lockSettings(c.other, exclusive)
+    return func() { unlockOther(); unlock() }
+}
+
+func (c *comparingEphemeris) Close() {
+    c.Ephemeris.Close()
+    c.other.Close()
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+    "bufio"
+    "bytes"
+    "math"
+    "strings"
+    "testing"
+)
+
+// shiftedEphemeris is the fake with every longitude moved by shift degrees.
+type shiftedEphemeris struct {
+    fakeEphemeris
+    shift float64
+}
+
+func (*shiftedEphemeris) Name() string { return "shifted" }
+
+func (s *shiftedEphemeris) CalcUt(jd float64, body, flags int, xx []float64) error {
+    if err := s.fakeEphemeris.CalcUt(jd, body, flags, xx); err != nil {
+        return err
+    }
+    xx[0] = normDeg(xx[0] + s.shift)
+    return nil
+}
+
+func newComparing(tolerance, shift float64) *comparingEphemeris {
+    return &comparingEphemeris{Ephemeris: &fakeEphemeris{}, other: &shiftedEphemeris{shift: shift},
+        tolerance: tolerance, worst: map[string]float64{}}
+}
+
+// discrepancies reads the count and sum of one series of the histogram.
+func discrepancies(call, backend string) (count uint64, sum float64) {
+    ephemerisDiscrepancy.mu.Lock()
+    defer ephemerisDiscrepancy.mu.Unlock()
+    if s, ok := ephemerisDiscrepancy.series[call+"\xff"+backend]; ok {
+        return s.count, s.sum
+    }
+    return 0, 0
+}
+
+func TestComparingEphemerisReportsDiscrepancy(t *testing.T) {
+    c := newComparing(1, 36.0/3600) // 36" apart
+    count0, sum0 := discrepancies("calc", "shifted")
+
+    xx := make([]float64, 6)
+    if err := c.CalcUt(2451545, SE_CHIRON, SEFLG_SPEED, xx); err != nil {
+        t.Fatal(err)
+    }
+    // Answers come from the primary
+    if want := normDeg(readinessRefChironLon); math.Abs(xx[0]-want) > 1e-9 {
+        t.Errorf("longitude %.6f, want the primary's %.6f", xx[0], want)
+    }
+    count, sum := discrepancies("calc", "shifted")
+    if count != count0+1 || math.Abs(sum-sum0-36) > 1e-6 {
+        t.Errorf("histogram recorded %d observations summing %.6f\", want one of 36\"", count-count0, sum-sum0)
+    }
+    if worst := c.worst["calc"]; math.Abs(worst-36) > 1e-6 {
+        t.Errorf("worst calc discrepancy %.6f\", want 36\"", worst)
+    }
+
+    // Houses and sidereal time agree, so nothing beyond the tolerance
+    cusps, ascmc := make([]float64, 13), make([]float64, 10)
+    if err := c.HousesEx(2451545, 0, 51.5, 0, 'E', cusps, ascmc); err != nil {
+        t.Fatal(err)
+    }
+    c.Sidtime(2451545)
+    if _, ok := c.worst["houses"]; ok {
+        t.Error("identical houses logged as a disagreement")
+    }
+
+    // The discrepancy shows up in /metrics
+    var buf bytes.Buffer
+    w := bufio.NewWriter(&buf)
+    ephemerisDiscrepancy.writeTo(w)
+    w.Flush()
+    if !strings.Contains(buf.String(), `chiron_ephemeris_discrepancy_arcseconds_count{call="calc",backend="shifted"}`) {
+        t.Errorf("metrics output lacks the calc series:\n%s", buf.String())
+    }
+}
+
+func TestComparingEphemerisWithinTolerance(t *testing.T) {
+    c := newComparing(60, 36.0/3600)
+    xx := make([]float64, 6)
+    if err := c.CalcUt(2451545, SE_MARS, SEFLG_SPEED, xx); err != nil {
+        t.Fatal(err)
+    }
+    if len(c.worst) != 0 {
+        t.Errorf("discrepancies within tolerance logged: %v", c.worst)
+    }
+}
+
+func TestComparingEphemerisWraparound(t *testing.T) {
+    // 359.99° against 0.01° is 72", not 359.98°
+    if d := arcsecDiff(359.99, 0.01); math.Abs(d-72) > 1e-6 {
+        t.Errorf("arcsecDiff across 0° = %.6f\", want 72\"", d)
+    }
+}
+
+func TestComparingEphemerisOtherFails(t *testing.T) {
+    c := newComparing(1, 0)
+    c.other = &approxEphemeris{}
+    // The pure-Go backend has no Koch houses; the primary's answer stands
+    cusps, ascmc := make([]float64, 13), make([]float64, 10)
+    if err := c.HousesEx(2451545, 0, 51.5, 0, 'K', cusps, ascmc); err != nil {
+        t.Fatalf("primary answer lost: %v", err)
+    }
+    if ascmc[0] == 0 {
+        t.Error("no Ascendant from the primary")
+    }
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+    "fmt"
+    "math"
+)
+
+// ===== Deterministic fake ephemeris =====
+
+// fakeEphemeris moves every body at a constant rate along the ecliptic from
+// its mean J2000 longitude, so tests get answers that are exact, cheap and
+// identical on every platform. The sky it describes is only roughly real:
+// latitudes are zero, distances one AU, and every house system other than
+// whole sign comes back as equal houses. Chiron matches the readiness check.
+type fakeEphemeris struct{}
+
+func newFakeEphemeris() Ephemeris { return &fakeEphemeris{} }
+
+const (
+    fakeObliquity  = 23.4392911              // J2000, held fixed
+    fakePrecession = 50.29 * arcsec / 365.25 // ayanamsa drift, degrees per day
+)
+
+// fakeOrbits holds each body's longitude at J2000 and daily motion.
+var fakeOrbits = map[int][2]float64{
+    SE_SUN:     {280.4665, 0.9856474},
+    SE_MOON:    {218.3165, 13.1763966},
+    SE_MERCURY: {252.2509, 4.0923344},
+    SE_VENUS:   {181.9798, 1.6021302},
+    SE_MARS:    {355.4330, 0.5240208},
+    SE_JUPITER: {34.3515, 0.0830853},
+    SE_SATURN:  {50.0774, 0.0334442},
+    SE_URANUS:  {314.0550, 0.0117308},
+    SE_NEPTUNE: {304.3487, 0.0059811},
+    SE_PLUTO:   {238.9290, 0.0039657},
+    SE_CHIRON:  {readinessRefChironLon, 0.0194175},
+}
+
+func (*fakeEphemeris) Name() string                  { return "fake" }
+func (*fakeEphemeris) SetTopo(lon, lat, alt float64) {}
+func (*fakeEphemeris) Close()                        {}
+func (*fakeEphemeris) SetSidMode(mode int)           {}
+
+func (*fakeEphemeris) JulDay(year, month, day int, hour float64) float64 {
+    return gregorianJulDay(year, month, day, hour)
+}
+
+func (f *fakeEphemeris) CalcUt(jd float64, body, flags int, xx []float64) error {
+    for i := range xx {
+        xx[i] = 0
+    }
+    if body == SE_ECL_NUT {
+        xx[0], xx[1] = fakeObliquity, fakeObliquity
+        return nil
+    }
+    orbit, ok := fakeOrbits[body]
+    if !ok {
+        return fmt.Errorf("body %d is not available in the fake ephemeris", body)
+    }
+    lon := normDeg(orbit[0] + orbit[1]*(jd-2451545))
+    xx[2], xx[3] = 1, orbit[1]
+    if flags&SEFLG_EQUATORIAL != 0 {
+        l, e := lon*deg2rad, fakeObliquity*deg2rad
+        xx[0] = normDeg(math.Atan2(math.Sin(l)*math.Cos(e), math.Cos(l)) / deg2rad)
+        xx[1] = math.Asin(math.Sin(e)*math.Sin(l)) / deg2rad
+        return nil
+    }
+    if flags&SEFLG_SIDEREAL != 0 {
+        ayan, _ := f.AyanamsaUt(jd)
+        lon = normDeg(lon - ayan)
+    }
+    xx[0] = lon
+    return nil
+}
+
+// Sidtime is mean sidereal time; the fake has no nutation.
+func (*fakeEphemeris) Sidtime(jd float64) float64 {
+    return normDeg(280.46061837+360.98564736629*(jd-2451545)) / 15
+}
+
+// AyanamsaUt drifts from Lahiri's J2000 value whatever the mode.
+func (*fakeEphemeris) AyanamsaUt(jd float64) (float64, error) {
+    return 23.857 + fakePrecession*(jd-2451545), nil
+}
+
+func (f *fakeEphemeris) HousesEx(jd float64, flags int, lat, lon float64, hsys int, cusps, ascmc []float64) error {
+    shift := 0.0
+    if flags&SEFLG_SIDEREAL != 0 {
+        shift, _ = f.AyanamsaUt(jd)
+    }
+    return f.houses(normDeg(f.Sidtime(jd)*15+lon), lat, fakeObliquity, hsys, shift, cusps, ascmc)
+}
+
+func (f *fakeEphemeris) HousesArmc(armc, lat, eps float64, hsys int, cusps, ascmc []float64) error {
+    return f.houses(armc, lat, eps, hsys, 0, cusps, ascmc)
+}
+
+func (*fakeEphemeris) houses(armc, lat, eps float64, hsys int, shift float64, cusps, ascmc []float64) error {
+    if hsys != 'W' {
+        hsys = 'E'
+    }
+    return approxHouses(armc, lat, eps, hsys, shift, cusps, ascmc)
+}
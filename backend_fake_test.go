@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+// useFake answers every ephemeris call in one test from fakeEphemeris, with
+// the Chiron table off so it can't answer first.
+func useFake(t *testing.T) *fakeEphemeris {
+    t.Helper()
+    return useEphemeris(t, &fakeEphemeris{}).(*fakeEphemeris)
+}
+
+// useEphemeris swaps in e and the default configuration for one test.
+func useEphemeris(t *testing.T, e Ephemeris) Ephemeris {
+    t.Helper()
+    savedEph, savedConfig := eph, appConfig
+    eph = e
+    appConfig = defaultConfig()
+    appConfig.Ephemeris.ChironTable = false
+    t.Cleanup(func() { eph, appConfig = savedEph, savedConfig })
+    return e
+}
+
+// fakeChironLon is Chiron's longitude in the fake at jd, worked out here
+// rather than through the backend.
+func fakeChironLon(jd float64) float64 {
+    return normDeg(readinessRefChironLon + 0.0194175*(jd-2451545))
+}
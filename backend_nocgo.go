@@ -0,0 +1,17 @@
+//go:build !cgo
+
+package main
+
+// Without cgo there is no Swiss Ephemeris; the auto backend falls back to the
+// pure-Go approximation and the others refuse to start.
+func newSwissEphemeris(path string) (Ephemeris, error) {
+    return nil, errNoCgo
+}
+
+func newMoshierEphemeris(path string) (Ephemeris, error) {
+    return nil, errNoCgo
+}
+
+func newJPLEphemeris(path, file string) (Ephemeris, error) {
+    return nil, errNoCgo
+}
@@ -0,0 +1,230 @@
+//go:build cgo
+
+package main
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "os"
+    "runtime"
+    "sync"
+
+    swe "github.com/mshafiee/swephgo"
+)
+
+// ephemerisSources are the flag bits choosing where libswe takes positions from.
+const ephemerisSources = SEFLG_JPLEPH | SEFLG_SWIEPH | SEFLG_MOSEPH
+
+// swissEphemeris calls the C Swiss Ephemeris through cgo. source picks the
+// data behind it: the .se1 files, the built-in Moshier theory or a JPL DE
+// file.
+type swissEphemeris struct {
+    name      string
+    source    int
+    do        *runner // runs libswe calls
+    asteroids *runner // runs Moshier's Chiron calls, nil otherwise
+    settings  settingsLock
+}
+
+var errEphemerisClosed = errors.New("ephemeris closed")
+
+// runner makes libswe calls, on the calling thread or, when calls is set, on
+// one locked OS thread. Once closed it refuses them.
+type runner struct {
+    mu     sync.RWMutex
+    calls  chan func()
+    closed bool
+}
+
+func direct() *runner { return &runner{} }
+
+func (r *runner) run(fn func()) error {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    if r.closed {
+        return errEphemerisClosed
+    }
+    if r.calls == nil {
+        fn()
+        return nil
+    }
+    done := make(chan struct{})
+    r.calls <- func() { fn(); close(done) }
+    <-done
+    return nil
+}
+
+// close waits for calls in flight, then lets the pinned goroutine and its
+// thread go.
+func (r *runner) close() {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    if !r.closed {
+        r.closed = true
+        if r.calls != nil {
+            close(r.calls)
+        }
+    }
+}
+
+func newSwissEphemeris(path string) (Ephemeris, error) {
+    setEphePath(path)
+    return &swissEphemeris{name: "swiss", source: SEFLG_SWIEPH, do: direct()}, nil
+}
+
+// newMoshierEphemeris keeps its calls off the threads the other backends use:
+// libswe reports its files damaged when one thread mixes Moshier and file
+// positions. Chiron, which Moshier lacks, comes from seas_*.se1 on a thread
+// of its own for the same reason.
+func newMoshierEphemeris(path string) (Ephemeris, error) {
+    setEphePath(path)
+    return &swissEphemeris{name: "moshier", source: SEFLG_MOSEPH,
+        do: pinnedThread(func() {}), asteroids: pinnedThread(func() {})}, nil
+}
+
+// newJPLEphemeris opens file, a JPL DE ephemeris in path, and fails unless
+// libswe can read it.
+func newJPLEphemeris(path, file string) (Ephemeris, error) {
+    setEphePath(path)
+    e := &swissEphemeris{name: "jpl", source: SEFLG_JPLEPH}
+    e.do = pinnedThread(func() { swe.SetJplFile(append([]byte(file), 0)) })
+    // libswe quietly falls back to other files when the JPL one is missing
+    xx := make([]float64, 6)
+    serr := make([]byte, 256)
+    var ret int32
+    e.do.run(func() { ret = swe.CalcUt(2451545, SE_SUN, SEFLG_JPLEPH, xx, serr) })
+    if ret < 0 || int(ret)&SEFLG_JPLEPH == 0 {
+        e.Close()
+        return nil, fmt.Errorf("JPL file %s unusable: %s", file, cString(serr))
+    }
+    return e, nil
+}
+
+// setEphePath points libswe at the ephemeris files. Its settings are per OS
+// thread, and a thread that has none reads SE_EPHE_PATH on first use, so the
+// variable reaches every thread where swe.SetEphePath would reach only this
+// one.
+func setEphePath(path string) {
+    if path != "" {
+        os.Setenv("SE_EPHE_PATH", path)
+        swe.SetEphePath(append([]byte(path), 0))
+    }
+}
+
+// pinnedThread returns a runner that makes every call on one locked OS
+// thread, set up once, for settings such as the JPL file that libswe only
+// applies to the thread that made them. The thread exits when the runner is
+// closed.
+func pinnedThread(setup func()) *runner {
+    r := &runner{calls: make(chan func())}
+    go func() {
+        runtime.LockOSThread()
+        defer runtime.UnlockOSThread()
+        setup()
+        for fn := range r.calls {
+            fn()
+        }
+    }()
+    return r
+}
+
+func (e *swissEphemeris) Name() string { return e.name }
+
+// lockSettings locks only backends with pinned threads, which every request
+// shares. Direct calls run on the caller's own pinned thread, where libswe
+// keeps settings apart from every other thread's.
+func (e *swissEphemeris) lockSettings(exclusive bool) func() {
+    if e.do.calls == nil {
+        return func() {}
+    }
+    return e.settings.lockSettings(exclusive)
+}
+
+func (*swissEphemeris) JulDay(year, month, day int, hour float64) float64 {
+    return swe.Julday(year, month, day, hour, SE_GREG_CAL)
+}
+
+func (e *swissEphemeris) CalcUt(jd float64, body, flags int, xx []float64) error {
+    run, source := e.do, e.source
+    if e.asteroids != nil && body == SE_CHIRON {
+        // Moshier has no asteroids
+        run, source = e.asteroids, SEFLG_SWIEPH
+    }
+    serr := make([]byte, 256)
+    var ret int32
+    if err := run.run(func() { ret = swe.CalcUt(jd, body, flags&^ephemerisSources|source, xx, serr) }); err != nil {
+        return err
+    }
+    if ret < 0 {
+        return errors.New(cString(serr))
+    }
+    return nil
+}
+
+func (e *swissEphemeris) HousesEx(jd float64, flags int, lat, lon float64, hsys int, cusps, ascmc []float64) error {
+    var ret int32
+    if err := e.do.run(func() { ret = swe.HousesEx(jd, flags&^ephemerisSources|e.source, lat, lon, hsys, cusps, ascmc) }); err != nil {
+        return err
+    }
+    if ret < 0 {
+        return errHouses
+    }
+    return nil
+}
+
+func (e *swissEphemeris) HousesArmc(armc, lat, eps float64, hsys int, cusps, ascmc []float64) error {
+    var ret int32
+    if err := e.do.run(func() { ret = swe.HousesArmc(armc, lat, eps, hsys, cusps, ascmc) }); err != nil {
+        return err
+    }
+    if ret < 0 {
+        return errHouses
+    }
+    return nil
+}
+
+// Sidtime is NaN once the backend is closed.
+func (e *swissEphemeris) Sidtime(jd float64) float64 {
+    var st float64
+    if err := e.do.run(func() { st = swe.Sidtime(jd) }); err != nil {
+        return math.NaN()
+    }
+    return st
+}
+
+func (e *swissEphemeris) AyanamsaUt(jd float64) (float64, error) {
+    daya := make([]float64, 1)
+    serr := make([]byte, 256)
+    var ret int32
+    if err := e.do.run(func() { ret = swe.GetAyanamsaExUt(jd, e.source, daya, serr) }); err != nil {
+        return 0, err
+    }
+    if ret < 0 {
+        return 0, errors.New(cString(serr))
+    }
+    return daya[0], nil
+}
+
+// each runs fn through every runner the backend uses.
+func (e *swissEphemeris) each(fn func()) {
+    e.do.run(fn)
+    if e.asteroids != nil {
+        e.asteroids.run(fn)
+    }
+}
+
+func (e *swissEphemeris) SetSidMode(mode int) { e.each(func() { swe.SetSidMode(mode, 0, 0) }) }
+func (e *swissEphemeris) SetTopo(lon, lat, alt float64) {
+    e.each(func() { swe.SetTopo(lon, lat, alt) })
+}
+
+// Close releases libswe's files and stops the pinned threads; later calls
+// fail with errEphemerisClosed.
+func (e *swissEphemeris) Close() {
+    e.each(swe.Close)
+    e.do.close()
+    if e.asteroids != nil {
+        e.asteroids.close()
+    }
+}
@@ -0,0 +1,97 @@
+//go:build cgo
+
+package main
+
+import (
+    "errors"
+    "runtime"
+    "sync"
+    "testing"
+    "time"
+)
+
+func TestPinnedThreadsStopOnClose(t *testing.T) {
+    before := runtime.NumGoroutine()
+    e, err := newMoshierEphemeris("swisseph/ephe")
+    if err != nil {
+        t.Fatal(err)
+    }
+    xx := make([]float64, 6)
+    if err := e.CalcUt(2451545, SE_SUN, SEFLG_SPEED, xx); err != nil {
+        t.Fatal(err)
+    }
+    if err := e.CalcUt(2451545, SE_CHIRON, SEFLG_SPEED, xx); err != nil {
+        t.Fatal(err)
+    }
+    e.Close()
+
+    // Calls after Close fail at once instead of waiting on a dead thread
+    done := make(chan error, 1)
+    go func() { done <- e.CalcUt(2451545, SE_SUN, SEFLG_SPEED, xx) }()
+    select {
+    case err := <-done:
+        if !errors.Is(err, errEphemerisClosed) {
+            t.Errorf("CalcUt after Close: %v, want errEphemerisClosed", err)
+        }
+    case <-time.After(5 * time.Second):
+        t.Fatal("CalcUt after Close blocked")
+    }
+    e.Close() // twice is harmless
+
+    deadline := time.Now().Add(5 * time.Second)
+    for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+        time.Sleep(10 * time.Millisecond)
+    }
+    if n := runtime.NumGoroutine(); n > before {
+        t.Errorf("%d goroutines after Close, %d before", n, before)
+    }
+}
+
+// Requests with different settings run side by side on the swiss and
+// moshier backends and still each get their own answer.
+func TestConcurrentSettingsStayApart(t *testing.T) {
+    for _, open := range []func(string) (Ephemeris, error){newSwissEphemeris, newMoshierEphemeris} {
+        e, err := open("swisseph/ephe")
+        if err != nil {
+            t.Fatal(err)
+        }
+        useEphemeris(t, e)
+        settings := []calcOptions{
+            tropical,
+            {Zodiac: "sidereal", Ayanamsa: "lahiri"},
+            {Zodiac: "sidereal", Ayanamsa: "fagan_bradley"},
+            {Zodiac: "tropical", Topocentric: true, Lat: 51.5, Lon: -0.12},
+            {Zodiac: "tropical", Topocentric: true, Lat: -33.9, Lon: 151.2, Altitude: 50},
+        }
+        at := func(o calcOptions) [2]float64 {
+            xx := make([]float64, 6)
+            var err error
+            withEphemeris(o, func() { err = eph.CalcUt(2451545, SE_MOON, o.flags(), xx) })
+            if err != nil {
+                t.Error(err)
+            }
+            return [2]float64{xx[0], xx[1]}
+        }
+        want := make([][2]float64, len(settings))
+        for i, o := range settings {
+            want[i] = at(o)
+        }
+
+        var wg sync.WaitGroup
+        for g := 0; g < 8; g++ {
+            wg.Add(1)
+            go func(g int) {
+                defer wg.Done()
+                for i := 0; i < 1500; i++ {
+                    k := (g + i) % len(settings)
+                    if got := at(settings[k]); got != want[k] {
+                        t.Errorf("%s settings %d: %v, want %v", e.Name(), k, got, want[k])
+                        return
+                    }
+                }
+            }(g)
+        }
+        wg.Wait()
+        e.Close()
+    }
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestCheckEphemerisDefaults(t *testing.T) {
+    approx := &approxEphemeris{}
+    cfg := defaultConfig().Ephemeris
+    if err := checkEphemerisDefaults(approx, cfg); err != nil {
+        t.Errorf("defaults: %v", err)
+    }
+    for _, system := range []string{"koch", "campanus", "alcabitius", "morinus", "topocentric"} {
+        cfg := defaultConfig().Ephemeris
+        cfg.HouseSystem = system
+        if err := checkEphemerisDefaults(approx, cfg); err == nil {
+            t.Errorf("%s houses accepted by the approx backend", system)
+        }
+        // Every system is fine where the backend states no limits
+        if err := checkEphemerisDefaults(&fakeEphemeris{}, cfg); err != nil {
+            t.Errorf("%s houses on the fake: %v", system, err)
+        }
+    }
+    cfg.Ayanamsa = "fagan_bradley"
+    if err := checkEphemerisDefaults(&lahiriOnly{}, cfg); err == nil {
+        t.Errorf("ayanamsa %s accepted by a Lahiri-only backend", cfg.Ayanamsa)
+    }
+}
+
+// lahiriOnly is the fake limited to the Lahiri ayanamsa.
+type lahiriOnly struct{ fakeEphemeris }
+
+func (*lahiriOnly) supportsHouseSystem(hsys byte) bool { return true }
+func (*lahiriOnly) supportsAyanamsa(mode int) bool     { return mode == ayanamsas["lahiri"] }
+func (*lahiriOnly) supportsTopocentric() bool          { return true }
+
+func TestUnsupportedOptionsAreBadRequests(t *testing.T) {
+    for name, c := range map[string]struct {
+        eph   Ephemeris
+        extra string
+    }{
+        "topocentric": {&approxEphemeris{}, `"topocentric": true`},
+        "ayanamsa":    {&lahiriOnly{}, `"zodiac": "sidereal", "ayanamsa": "fagan_bradley"`},
+    } {
+        useEphemeris(t, c.eph)
+        body := `{"year": 1990, "month": 6, "day": 15, "hour": 12, "timezone": "UTC", ` + c.extra + `}`
+        r := httptest.NewRequest(http.MethodPost, "/api/chiron", strings.NewReader(body))
+        w := httptest.NewRecorder()
+        chironHandler(w, r)
+        if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "not available") {
+            t.Errorf("%s: status %d %q, want 400", name, w.Code, w.Body.String())
+        }
+    }
+}
+
+func TestApproxRefusesToOpenWithoutChironTable(t *testing.T) {
+    savedTable, savedErr := chironTable, chironTableErr
+    chironTable, chironTableErr = parseChebTable(nil)
+    t.Cleanup(func() { chironTable, chironTableErr = savedTable, savedErr })
+
+    if _, err := openEphemeris(ephemerisConfig{Backend: "approx"}); err == nil {
+        t.Error("approx opened without the Chiron table")
+    }
+    // The default backend answers with an error rather than a panic
+    if err := (&approxEphemeris{}).CalcUt(2451545, SE_CHIRON, 0, make([]float64, 6)); err == nil {
+        t.Error("Chiron computed without the table")
+    }
+}
+
+// Tropical geocentric calls share the approx backend's settings lock, so
+// they never wait on one another.
+func TestTropicalCallsRunSideBySide(t *testing.T) {
+    useEphemeris(t, &approxEphemeris{})
+    inside := make(chan struct{})
+    done := make(chan struct{})
+    go func() {
+        withEphemeris(tropical, func() {
+            close(inside)
+            <-done
+        })
+    }()
+    <-inside
+    finished := make(chan struct{})
+    go func() {
+        withEphemeris(tropical, func() {})
+        close(finished)
+    }()
+    select {
+    case <-finished:
+    case <-time.After(5 * time.Second):
+        t.Error("a tropical call waited for another")
+    }
+    close(done)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+    _ "embed"
+    "encoding/binary"
+    "errors"
+    "math"
+)
+
+// ===== Precomputed Chiron table =====
+
+// chiron_table.bin holds Chebyshev coefficients for tropical geocentric
+// Chiron, 1800-2200, in 32-day segments. Regenerating it also prints its
+// error against the live ephemeris.
+//
+//go:generate go run ./tools/chirontable -o chiron_table.bin
+//go:embed chiron_table.bin
+var chironTableData []byte
+
+type chebTable struct {
+    start, segDays float64 // JD (UT) of the first segment, segment length
+    segments       int
+    ncoef          int
+    coef           []float32 // per segment: longitude, latitude, distance series
+}
+
+var chironTable, chironTableErr = parseChebTable(chironTableData)
+
+func parseChebTable(b []byte) (*chebTable, error) {
+    const headerLen = 32
+    if len(b) < headerLen || string(b[:4]) != "CHEB" || binary.LittleEndian.Uint32(b[4:]) != 1 {
+        return nil, errors.New("chiron table: bad header")
+    }
+    t := &chebTable{
+        start:    math.Float64frombits(binary.LittleEndian.Uint64(b[8:])),
+        segDays:  math.Float64frombits(binary.LittleEndian.Uint64(b[16:])),
+        segments: int(binary.LittleEndian.Uint32(b[24:])),
+        ncoef:    int(binary.LittleEndian.Uint32(b[28:])),
+    }
+    n := t.segments * 3 * t.ncoef
+    if t.ncoef < 2 || len(b) != headerLen+4*n {
+        return nil, errors.New("chiron table: truncated")
+    }
+    t.coef = make([]float32, n)
+    for i := range t.coef {
+        t.coef[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[headerLen+4*i:]))
+    }
+    return t, nil
+}
+
+// chebEval returns a Chebyshev series and its derivative at x in [-1, 1].
+func chebEval(c []float32, x float64) (v, d float64) {
+    t0, t1 := 1.0, x
+    u0, u1 := 0.0, 1.0 // derivatives of t0, t1
+    v = float64(c[0]) + float64(c[1])*x
+    d = float64(c[1])
+    for j := 2; j < len(c); j++ {
+        t2 := 2*x*t1 - t0
+        u2 := 2*t1 + 2*x*u1 - u0
+        v += float64(c[j]) * t2
+        d += float64(c[j]) * u2
+        t0, t1, u0, u1 = t1, t2, u1, u2
+    }
+    return v, d
+}
+
+// at returns Chiron's longitude, latitude, distance and daily speed, or false
+// outside the table.
+func (t *chebTable) at(jd float64) (lon, lat, dist, speed float64, ok bool) {
+    s := int(math.Floor((jd - t.start) / t.segDays))
+    if s < 0 || s >= t.segments {
+        return 0, 0, 0, 0, false
+    }
+    x := 2*(jd-t.start-float64(s)*t.segDays)/t.segDays - 1
+    c := t.coef[s*3*t.ncoef:]
+    lon, dl := chebEval(c[:t.ncoef], x)
+    lat, _ = chebEval(c[t.ncoef:2*t.ncoef], x)
+    dist, _ = chebEval(c[2*t.ncoef:3*t.ncoef], x)
+    lon = math.Mod(lon+360, 360)
+    return lon, lat, dist, dl * 2 / t.segDays, true
+}
+
+// chironFromTable answers tropical geocentric requests from the table when
+// it's enabled and covers jd.
+func chironFromTable(jd float64, flags int) (lon, speed float64, ok bool) {
+    if !chironTableUsable(flags) {
+        return 0, 0, false
+    }
+    lon, _, _, speed, ok = chironTable.at(jd)
+    return lon, speed, ok
+}
+
+// chironTableUsable reports whether the table may answer a request with
+// these flags: it holds tropical geocentric positions only.
+func chironTableUsable(flags int) bool {
+    return chironTable != nil && appConfig.Ephemeris.ChironTable &&
+        flags&(SEFLG_SIDEREAL|SEFLG_HELCTR|SEFLG_TOPOCTR) == 0
+}
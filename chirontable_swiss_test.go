@@ -0,0 +1,90 @@
+//go:build cgo
+
+package main
+
+import (
+    "math"
+    "testing"
+)
+
+// useSwiss points the package at the Swiss Ephemeris files for one test.
+func useSwiss(t *testing.T) {
+    t.Helper()
+    e, err := newSwissEphemeris("swisseph/ephe")
+    if err != nil {
+        t.Fatal(err)
+    }
+    saved := eph
+    eph = e
+    t.Cleanup(func() { eph = saved })
+}
+
+func TestChironTableMatchesEphemeris(t *testing.T) {
+    useSwiss(t)
+    if chironTableErr != nil {
+        t.Fatal(chironTableErr)
+    }
+    from := eph.JulDay(1800, 1, 1, 0)
+    to := eph.JulDay(2200, 12, 31, 0)
+    var worstLon, worstSpeed, worstDec float64
+    // A step that isn't a multiple of the segment length lands all over them
+    for jd := from + 0.3; jd < to; jd += 9.7 {
+        live, err := liveChironPosition(jd, tropical)
+        if err != nil {
+            t.Fatalf("jd %.1f: %v", jd, err)
+        }
+        lon, lat, dist, speed, ok := chironTable.at(jd)
+        if !ok {
+            t.Fatalf("jd %.1f: outside the table", jd)
+        }
+        worstLon = math.Max(worstLon, arcsecDiff(lon, live.Lon))
+        worstSpeed = math.Max(worstSpeed, math.Abs(speed-live.Speed)*3600)
+        if d := math.Abs(lat-live.Lat) * 3600; d > 5 {
+            t.Errorf("jd %.1f: latitude off by %.2f\"", jd, d)
+        }
+        if d := math.Abs(dist - live.Dist); d > 1e-5 {
+            t.Errorf("jd %.1f: distance off by %g AU", jd, d)
+        }
+
+        // The /api/chiron path, with right ascension and declination derived
+        pos, err := computeChironPosition(jd, tropical)
+        if err != nil {
+            t.Fatalf("jd %.1f: %v", jd, err)
+        }
+        worstDec = math.Max(worstDec, math.Max(arcsecDiff(pos.RA, live.RA), math.Abs(pos.Dec-live.Dec)*3600))
+    }
+    t.Logf("worst longitude %.2f\", speed %.2f\"/day, equatorial %.2f\"", worstLon, worstSpeed, worstDec)
+    if worstLon > 5 {
+        t.Errorf("longitude off by up to %.2f\"", worstLon)
+    }
+    // Swiss Ephemeris's own speeds jump a few arcseconds where its Chiron
+    // series is stitched together, which a smooth fit can't follow
+    if worstSpeed > 10 {
+        t.Errorf("speed off by up to %.2f\"/day", worstSpeed)
+    }
+    if worstDec > 5 {
+        t.Errorf("right ascension or declination off by up to %.2f\"", worstDec)
+    }
+}
+
+func TestChironTableEdgesMatchEphemeris(t *testing.T) {
+    useSwiss(t)
+    if chironTableErr != nil {
+        t.Fatal(chironTableErr)
+    }
+    first := chironTable.start
+    end := first + float64(chironTable.segments)*chironTable.segDays
+    for _, jd := range []float64{first, end - 1e-6} {
+        lon, _, _, _, ok := chironTable.at(jd)
+        if !ok {
+            t.Fatalf("jd %.6f: no answer at the edge of the table", jd)
+        }
+        live, err := liveChironPosition(jd, tropical)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if d := arcsecDiff(lon, live.Lon); d > 5 {
+            t.Errorf("jd %.6f: longitude off by %.2f\" at the edge", jd, d)
+        }
+    }
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+    "math"
+    "testing"
+)
+
+func TestChironTableBounds(t *testing.T) {
+    if chironTableErr != nil {
+        t.Fatal(chironTableErr)
+    }
+    first := chironTable.start
+    end := first + float64(chironTable.segments)*chironTable.segDays
+    if first > gregorianJulDay(1800, 1, 1, 0) || end < gregorianJulDay(2201, 1, 1, 0) {
+        t.Fatalf("table covers JD %.1f to %.1f, not 1800-2200", first, end)
+    }
+    for _, jd := range []float64{first - 1e-6, end, end + 1} {
+        if _, _, _, _, ok := chironTable.at(jd); ok {
+            t.Errorf("jd %.6f: answered outside the table", jd)
+        }
+    }
+    for _, jd := range []float64{first, end - 1e-6} {
+        if _, _, _, _, ok := chironTable.at(jd); !ok {
+            t.Errorf("jd %.6f: no answer at the edge of the table", jd)
+        }
+    }
+}
+
+func TestChironTableKnownPosition(t *testing.T) {
+    if chironTableErr != nil {
+        t.Fatal(chironTableErr)
+    }
+    // The readiness check's reference: Chiron at J2000
+    lon, _, _, _, ok := chironTable.at(readinessRefJD)
+    if !ok || math.Abs(lon-readinessRefChironLon) > readinessTolerance {
+        t.Errorf("J2000 longitude %.4f, want %.4f", lon, readinessRefChironLon)
+    }
+}
+
+func TestChironTableContinuousAcrossSegments(t *testing.T) {
+    if chironTableErr != nil {
+        t.Fatal(chironTableErr)
+    }
+    // Segments are fitted separately, so they meet to within the fit error
+    // rather than exactly
+    for s := 1; s < chironTable.segments; s++ {
+        jd := chironTable.start + float64(s)*chironTable.segDays
+        lon1, lat1, dist1, speed1, _ := chironTable.at(jd - 1e-7)
+        lon2, lat2, dist2, speed2, _ := chironTable.at(jd)
+        if d := arcsecDiff(lon1, lon2); d > 1 {
+            t.Errorf("segment %d: longitude jumps %.2f\"", s, d)
+        }
+        if d := math.Abs(lat1-lat2) * 3600; d > 1 {
+            t.Errorf("segment %d: latitude jumps %.2f\"", s, d)
+        }
+        if d := math.Abs(dist1 - dist2); d > 1e-5 {
+            t.Errorf("segment %d: distance jumps %g AU", s, d)
+        }
+        if d := math.Abs(speed1-speed2) * 3600; d > 10 {
+            t.Errorf("segment %d: speed jumps %.2f\"/day", s, d)
+        }
+    }
+}
+
+func TestParseChebTableRejectsBadData(t *testing.T) {
+    bad := map[string][]byte{
+        "empty":     nil,
+        "short":     chironTableData[:16],
+        "magic":     append([]byte("BEHC"), chironTableData[4:]...),
+        "version":   append(append([]byte{}, chironTableData[:4]...), append([]byte{2, 0, 0, 0}, chironTableData[8:]...)...),
+        "truncated": chironTableData[:len(chironTableData)-4],
+        "overlong":  append(append([]byte{}, chironTableData...), 0, 0, 0, 0),
+    }
+    for name, b := range bad {
+        if _, err := parseChebTable(b); err == nil {
+            t.Errorf("%s: parsed without error", name)
+        }
+    }
+    if _, err := parseChebTable(chironTableData); err != nil {
+        t.Errorf("embedded table: %v", err)
+    }
+}
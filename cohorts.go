@@ -0,0 +1,320 @@
+package main
+
+import (
+    "fmt"
+    "log/slog"
+    "math"
+    "net/http"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+)
+
+// ===== Generational cohorts =====
+
+const (
+    cohortScanStep = 2.0 // days; Chiron never crosses a sign boundary and back this quickly
+    cohortMergeGap = 5.0 // years; stays closer than this belong to one pass through a sign
+)
+
+// SignStay is one continuous stretch of Chiron in a sign.
+type SignStay struct {
+    Sign      string  `json:"sign"`
+    From      string  `json:"from"` // RFC 3339, UTC; the range start for the first stay
+    To        string  `json:"to"`   // the range end for the last stay
+    Days      float64 `json:"days"`
+    Direction string  `json:"direction"`          // motion when entering: direct or retrograde
+    Reentry   bool    `json:"retrograde_reentry"` // back into a sign it had just left, moving retrograde
+
+    fromJD, toJD float64
+}
+
+// Cohort is a whole pass through a sign, from the first ingress to the final
+// egress, re-entries included.
+type Cohort struct {
+    Sign    string  `json:"sign"`
+    From    string  `json:"from"`
+    To      string  `json:"to"`
+    Years   float64 `json:"years"`
+    Stays   int     `json:"stays"`
+    Partial bool    `json:"partial,omitempty"` // cut by the cohort_start_year-cohort_end_year range
+
+    fromJD, toJD float64
+}
+
+type CohortSpan struct {
+    Sign string `json:"sign"`
+    From string `json:"from"`
+    To   string `json:"to"`
+}
+
+type CohortsReading struct {
+    Cohorts   []Cohort     `json:"cohorts,omitempty"`
+    Stays     []SignStay   `json:"stays,omitempty"`
+    Signs     []CohortSpan `json:"signs,omitempty"` // born_from/born_to queries only
+    Statement string       `json:"statement,omitempty"`
+    Zodiac    string       `json:"zodiac"`
+    Ayanamsa  string       `json:"ayanamsa,omitempty"`
+}
+
+const cohortChunkDays = 3650.0 // days scanned per hold of the ephemeris lock
+
+// Sign stays are computed once per zodiac setting over the configured range.
+// Concurrent requests for a setting still being scanned wait for that scan.
+var cohortCache = struct {
+    sync.Mutex
+    stays    map[string][]SignStay
+    inflight map[string]*cohortScan
+}{stays: map[string][]SignStay{}, inflight: map[string]*cohortScan{}}
+
+type cohortScan struct {
+    done  chan struct{}
+    stays []SignStay
+    err   error
+}
+
+func cohortStays(opts calcOptions) ([]SignStay, error) {
+    key := opts.Zodiac + "/" + opts.Ayanamsa
+    cohortCache.Lock()
+    if stays, ok := cohortCache.stays[key]; ok {
+        cohortCache.Unlock()
+        return stays, nil
+    }
+    if scan, ok := cohortCache.inflight[key]; ok {
+        cohortCache.Unlock()
+        <-scan.done
+        return scan.stays, scan.err
+    }
+    scan := &cohortScan{done: make(chan struct{})}
+    cohortCache.inflight[key] = scan
+    cohortCache.Unlock()
+
+    start := julianDay(time.Date(appConfig.Ephemeris.CohortStartYear, 1, 1, 0, 0, 0, 0, time.UTC))
+    end := julianDay(time.Date(appConfig.Ephemeris.CohortEndYear+1, 1, 1, 0, 0, 0, 0, time.UTC))
+    scan.stays, scan.err = computeSignStays(start, end, opts)
+
+    cohortCache.Lock()
+    delete(cohortCache.inflight, key)
+    if scan.err == nil {
+        cohortCache.stays[key] = scan.stays
+    }
+    cohortCache.Unlock()
+    close(scan.done)
+    return scan.stays, scan.err
+}
+
+// warmCohorts scans the tropical stays in the background at startup, so the
+// first /api/cohorts request doesn't wait for them.
+func warmCohorts() {
+    start := time.Now()
+    if _, err := cohortStays(tropical); err != nil {
+        slog.Warn("precomputing tropical cohorts", "err", err)
+        return
+    }
+    slog.Debug("tropical cohorts precomputed", "duration_ms", time.Since(start).Milliseconds())
+}
+
+// computeSignStays scans [start, end) for sign changes and bisects each to a
+// minute. It takes the ephemeris lock one cohortChunkDays chunk at a time,
+// so other requests get in between.
+func computeSignStays(start, end float64, opts calcOptions) ([]SignStay, error) {
+    var stays []SignStay
+    var cur SignStay
+    var prevJD float64
+    var err error
+    withEphemeris(opts, func() {
+        var lon, speed float64
+        if lon, speed, err = chironSpeedAt(start, opts.flags()); err != nil {
+            return
+        }
+        cur = SignStay{Sign: signFromLongitude(lon), fromJD: start, Direction: motion(speed)}
+        prevJD = start
+    })
+    for chunkStart := start; err == nil && chunkStart < end; chunkStart += cohortChunkDays {
+        chunkEnd := math.Min(chunkStart+cohortChunkDays, end)
+        withEphemeris(opts, func() {
+            for jd := prevJD + cohortScanStep; ; jd += cohortScanStep {
+                jd = math.Min(jd, chunkEnd)
+                var lon, speed float64
+                if lon, _, err = chironSpeedAt(jd, opts.flags()); err != nil {
+                    return
+                }
+                if sign := signFromLongitude(lon); sign != cur.Sign {
+                    a, b := prevJD, jd
+                    for b-a > stationPrecision {
+                        mid := (a + b) / 2
+                        var l float64
+                        if l, _, err = chironSpeedAt(mid, opts.flags()); err != nil {
+                            return
+                        }
+                        if signFromLongitude(l) == cur.Sign {
+                            a = mid
+                        } else {
+                            b = mid
+                        }
+                    }
+                    if _, speed, err = chironSpeedAt(b, opts.flags()); err != nil {
+                        return
+                    }
+                    cur.toJD = b
+                    stays = append(stays, cur)
+                    cur = SignStay{Sign: sign, fromJD: b, Direction: motion(speed)}
+                }
+                prevJD = jd
+                if jd >= chunkEnd {
+                    return
+                }
+            }
+        })
+    }
+    if err != nil {
+        ephemerisErrors.inc("chiron")
+        return nil, err
+    }
+    cur.toJD = end
+    stays = append(stays, cur)
+    for i := range stays {
+        s := &stays[i]
+        s.From = jdToTime(s.fromJD).Format(time.RFC3339)
+        s.To = jdToTime(s.toJD).Format(time.RFC3339)
+        s.Days = math.Round((s.toJD-s.fromJD)*100) / 100
+        s.Reentry = i >= 2 && stays[i-2].Sign == s.Sign && s.Direction == "retrograde"
+    }
+    return stays, nil
+}
+
+func motion(speed float64) string {
+    if speed < 0 {
+        return "retrograde"
+    }
+    return "direct"
+}
+
+// groupCohorts merges a sign's stays that are part of the same pass. stays
+// is the whole cached range, whose first and last stays are cut at its ends.
+func groupCohorts(stays []SignStay) []Cohort {
+    var cohorts []Cohort
+    last := map[string]int{} // sign -> index in cohorts of its latest pass
+    for i, s := range stays {
+        c, ok := last[s.Sign]
+        if ok && (s.fromJD-cohorts[c].toJD)/tropicalYear < cohortMergeGap {
+            cohorts[c].To, cohorts[c].toJD = s.To, s.toJD
+            cohorts[c].Stays++
+        } else {
+            c = len(cohorts)
+            last[s.Sign] = c
+            cohorts = append(cohorts, Cohort{Sign: s.Sign, From: s.From, To: s.To, Stays: 1, fromJD: s.fromJD, toJD: s.toJD})
+        }
+        if i == 0 || i == len(stays)-1 {
+            cohorts[c].Partial = true
+        }
+    }
+    for i := range cohorts {
+        cohorts[i].Years = math.Round((cohorts[i].toJD-cohorts[i].fromJD)/tropicalYear*100) / 100
+    }
+    return cohorts
+}
+
+// bornBetween clips the stays to [from, to] and describes the result.
+func bornBetween(stays []SignStay, from, to time.Time) ([]CohortSpan, string) {
+    a, b := julianDay(from), julianDay(to)
+    var spans []CohortSpan
+    signs := map[string]bool{}
+    var order []string
+    for _, s := range stays {
+        if s.toJD <= a || s.fromJD >= b {
+            continue
+        }
+        spans = append(spans, CohortSpan{
+            Sign: s.Sign,
+            From: jdToTime(math.Max(s.fromJD, a)).Format(time.RFC3339),
+            To:   jdToTime(math.Min(s.toJD, b)).Format(time.RFC3339),
+        })
+        if !signs[s.Sign] {
+            signs[s.Sign] = true
+            order = append(order, s.Sign)
+        }
+    }
+    period := fmt.Sprintf("born between %s and %s", from.Format("2006-01-02"), to.AddDate(0, 0, -1).Format("2006-01-02"))
+    if len(order) == 1 {
+        return spans, fmt.Sprintf("Everyone %s has Chiron in %s.", period, order[0])
+    }
+    list := strings.Join(order[:len(order)-1], ", ") + " or " + order[len(order)-1]
+    return spans, fmt.Sprintf("People %s have Chiron in %s, depending on their birth date.", period, list)
+}
+
+// cohortsHandler serves GET /api/cohorts. Without born_from/born_to it lists
+// every sign stay and pass between start and end (years); with them it says
+// which signs that birth period covers.
+func cohortsHandler(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+    q := r.URL.Query()
+    opts, err := resolveCalcOptions(BirthData{Zodiac: q.Get("zodiac"), Ayanamsa: q.Get("ayanamsa")})
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    minYear, maxYear := appConfig.Ephemeris.CohortStartYear, appConfig.Ephemeris.CohortEndYear
+    rangeStart := time.Date(minYear, 1, 1, 0, 0, 0, 0, time.UTC)
+    rangeEnd := time.Date(maxYear+1, 1, 1, 0, 0, 0, 0, time.UTC)
+
+    var from, to time.Time
+    bornQuery := q.Get("born_from") != "" || q.Get("born_to") != ""
+    if bornQuery {
+        if from, err = time.Parse("2006-01-02", q.Get("born_from")); err == nil {
+            to, err = time.Parse("2006-01-02", q.Get("born_to"))
+        }
+        if err != nil {
+            http.Error(w, "born_from and born_to must both be YYYY-MM-DD", http.StatusBadRequest)
+            return
+        }
+        to = to.AddDate(0, 0, 1) // inclusive
+    } else {
+        startYear, endYear := minYear, maxYear
+        for name, p := range map[string]*int{"start": &startYear, "end": &endYear} {
+            if v := q.Get(name); v != "" {
+                if *p, err = strconv.Atoi(v); err != nil {
+                    http.Error(w, name+" must be a year", http.StatusBadRequest)
+                    return
+                }
+            }
+        }
+        from = time.Date(startYear, 1, 1, 0, 0, 0, 0, time.UTC)
+        to = time.Date(endYear+1, 1, 1, 0, 0, 0, 0, time.UTC)
+    }
+    if !from.Before(to) || from.Before(rangeStart) || to.After(rangeEnd) {
+        http.Error(w, fmt.Sprintf("range must fall within %d-%d", minYear, maxYear), http.StatusBadRequest)
+        return
+    }
+
+    ephStart := time.Now()
+    stays, err := cohortStays(opts)
+    observeEphemeris(r.Context(), "cohorts", ephStart)
+    if err != nil {
+        http.Error(w, "ephemeris unavailable", http.StatusInternalServerError)
+        return
+    }
+
+    resp := CohortsReading{Zodiac: opts.Zodiac, Ayanamsa: opts.Ayanamsa}
+    if bornQuery {
+        resp.Signs, resp.Statement = bornBetween(stays, from, to)
+    } else {
+        // Whole passes, including those running past either end of the query
+        a, b := julianDay(from), julianDay(to)
+        for _, s := range stays {
+            if s.toJD > a && s.fromJD < b {
+                resp.Stays = append(resp.Stays, s)
+            }
+        }
+        for _, c := range groupCohorts(stays) {
+            if c.toJD > a && c.fromJD < b {
+                resp.Cohorts = append(resp.Cohorts, c)
+            }
+        }
+    }
+    writeJSON(w, http.StatusOK, resp)
+}
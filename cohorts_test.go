@@ -0,0 +1,161 @@
+package main
+
+import (
+    "encoding/json"
+    "math"
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "testing"
+    "time"
+)
+
+func TestComputeSignStaysAcrossChunks(t *testing.T) {
+    useFake(t)
+    start := 2451545.0
+    end := start + 4*cohortChunkDays + 17 // several chunks and a ragged end
+    stays, err := computeSignStays(start, end, tropical)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if stays[0].fromJD != start || stays[len(stays)-1].toJD != end {
+        t.Errorf("stays cover %.1f-%.1f, want %.1f-%.1f", stays[0].fromJD, stays[len(stays)-1].toJD, start, end)
+    }
+    const daysPerSign = 30 / 0.0194175
+    if want := int((end-start)/daysPerSign) + 2; len(stays) < want-1 || len(stays) > want {
+        t.Errorf("%d stays, want about %d", len(stays), want)
+    }
+    for i, s := range stays {
+        if i > 0 && s.fromJD != stays[i-1].toJD {
+            t.Errorf("stay %d starts at %.5f, previous ended at %.5f", i, s.fromJD, stays[i-1].toJD)
+        }
+        if i > 0 {
+            // Every ingress lands on a multiple of 30°
+            lon := fakeChironLon(s.fromJD)
+            if d := math.Abs(math.Remainder(lon, 30)); d > 0.0194175*2*stationPrecision {
+                t.Errorf("stay %d (%s) begins %.6f° from a sign boundary", i, s.Sign, d)
+            }
+            if s.Sign == stays[i-1].Sign || s.Direction != "direct" || s.Reentry {
+                t.Errorf("stay %d: %+v after %s", i, s, stays[i-1].Sign)
+            }
+        }
+    }
+}
+
+// emptyCohortCache sets the cohort range and starts the cache afresh for one
+// test.
+func emptyCohortCache(t *testing.T, startYear, endYear int) {
+    appConfig.Ephemeris.CohortStartYear, appConfig.Ephemeris.CohortEndYear = startYear, endYear
+    cohortCache.Lock()
+    saved := cohortCache.stays
+    cohortCache.stays = map[string][]SignStay{}
+    cohortCache.Unlock()
+    t.Cleanup(func() {
+        cohortCache.Lock()
+        cohortCache.stays = saved
+        cohortCache.Unlock()
+    })
+}
+
+func TestCohortStaysScansOncePerKey(t *testing.T) {
+    useFake(t)
+    emptyCohortCache(t, 1900, 2000)
+
+    var wg sync.WaitGroup
+    results := make([][]SignStay, 8)
+    for i := range results {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            stays, err := cohortStays(tropical)
+            if err != nil {
+                t.Error(err)
+            }
+            results[i] = stays
+        }(i)
+    }
+    wg.Wait()
+    for i, stays := range results {
+        if len(stays) == 0 || &stays[0] != &results[0][0] {
+            t.Errorf("caller %d got a separate scan", i)
+        }
+    }
+    if len(cohortCache.inflight) != 0 {
+        t.Errorf("%d scans still marked in flight", len(cohortCache.inflight))
+    }
+}
+
+// loopingEphemeris is the fake with Chiron drifting forwards across 120° in
+// yearly loops, so it crosses that boundary several times each way.
+type loopingEphemeris struct{ fakeEphemeris }
+
+func (l *loopingEphemeris) CalcUt(jd float64, body, flags int, xx []float64) error {
+    if err := l.fakeEphemeris.CalcUt(jd, body, flags, xx); err != nil || body != SE_CHIRON {
+        return err
+    }
+    omega := 2 * math.Pi / 365
+    xx[0] = 119 + 0.01*(jd-2451545) + 3*math.Sin(omega*(jd-2451545))
+    xx[3] = 0.01 + 3*omega*math.Cos(omega*(jd-2451545))
+    return nil
+}
+
+func TestReentryOnlyWhenRetrograde(t *testing.T) {
+    useEphemeris(t, &loopingEphemeris{})
+    stays, err := computeSignStays(2451545-400, 2451545+400, tropical)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var direct, retrograde int
+    for i, s := range stays {
+        if i < 2 || stays[i-2].Sign != s.Sign {
+            continue
+        }
+        if s.Direction == "retrograde" {
+            retrograde++
+        } else {
+            direct++
+        }
+        if s.Reentry != (s.Direction == "retrograde") {
+            t.Errorf("%s from %s entered %s: retrograde_reentry %v", s.Sign, s.From, s.Direction, s.Reentry)
+        }
+    }
+    if direct == 0 || retrograde == 0 {
+        t.Fatalf("%d direct and %d retrograde returns to a sign; want both", direct, retrograde)
+    }
+}
+
+func TestCohortsSpanWholePasses(t *testing.T) {
+    useFake(t)
+    emptyCohortCache(t, 1900, 2000)
+    w := httptest.NewRecorder()
+    cohortsHandler(w, httptest.NewRequest(http.MethodGet, "/api/cohorts?start=1950&end=1950", nil))
+    var resp CohortsReading
+    if err := json.Unmarshal(w.Body.Bytes(), &resp); w.Code != http.StatusOK || err != nil {
+        t.Fatalf("status %d, %v", w.Code, err)
+    }
+    if len(resp.Cohorts) == 0 {
+        t.Fatal("no cohorts")
+    }
+    // Chiron spends about four years in each sign of the fake, so the passes
+    // overlapping 1950 run well past the year itself
+    first, _ := time.Parse(time.RFC3339, resp.Cohorts[0].From)
+    last, _ := time.Parse(time.RFC3339, resp.Cohorts[len(resp.Cohorts)-1].To)
+    if first.Year() >= 1950 || last.Year() <= 1950 {
+        t.Errorf("cohorts run %s to %s, want whole passes around 1950", first, last)
+    }
+    for _, c := range resp.Cohorts {
+        if c.Partial || c.Years < 3 {
+            t.Errorf("%+v cut short", c)
+        }
+    }
+
+    // The passes at the ends of the cached range are cut by it
+    stays, err := cohortStays(tropical)
+    if err != nil {
+        t.Fatal(err)
+    }
+    all := groupCohorts(stays)
+    if !all[0].Partial || !all[len(all)-1].Partial {
+        t.Errorf("first %+v and last %+v not partial", all[0], all[len(all)-1])
+    }
+}
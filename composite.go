@@ -0,0 +1,203 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "math"
+    "net/http"
+    "time"
+)
+
+// ===== Composite and Davison charts =====
+
+type relationshipRequest struct {
+    PersonA BirthData `json:"person_a"`
+    PersonB BirthData `json:"person_b"`
+}
+
+type RelationshipChiron struct {
+    Method           string  `json:"method"` // composite or davison
+    Sign             string  `json:"sign"`
+    Degree           float64 `json:"degree"`
+    House            int     `json:"house"`
+    Ascendant        float64 `json:"ascendant"`
+    RelationshipSign string  `json:"relationship_wound"`
+    RelationshipHome string  `json:"relationship_house"`
+    Moment           string  `json:"moment,omitempty"` // Davison only: midpoint time, UTC
+    Lat              float64 `json:"lat"`
+    Lon              float64 `json:"lon"`
+}
+
+type RelationshipReading struct {
+    Composite RelationshipChiron `json:"composite"`
+    Davison   RelationshipChiron `json:"davison"`
+    Zodiac    string             `json:"zodiac"`
+    Ayanamsa  string             `json:"ayanamsa,omitempty"`
+}
+
+// relationshipSignTexts describe the wound the couple shares, by composite sign.
+var relationshipSignTexts = map[string]string{
+    "Aries":       "Together you carry a wound around asserting yourselves: one of you may feel erased whenever the other takes the lead. Healing comes from making room for two strong wills.",
+    "Taurus":      "The shared wound concerns security and worth. Money, comfort or physical affection can become a test of whether you are valued; steadiness rebuilt together is the cure.",
+    "Gemini":      "The relationship is tender around communication. Misunderstandings cut deeper than they should; learning to really listen heals what words once broke.",
+    "Cancer":      "The bond touches old family hurts. Each may look to the other for the mothering they missed; building a home that holds both of you is the healing.",
+    "Leo":         "The shared wound is about being seen. Competition for attention or a fear of outshining each other can creep in; generous recognition turns it around.",
+    "Virgo":       "Criticism is the sore spot. Trying to fix each other wounds more than it helps; practical care offered without judgement heals.",
+    "Libra":       "The wound lives in the balance of the partnership itself: who gives, who yields, what is fair. Honest negotiation rather than peace-keeping is the medicine.",
+    "Scorpio":     "Trust and intimacy carry the wound. Jealousy, secrets or power struggles echo old betrayals; deep honesty transforms them.",
+    "Sagittarius": "Beliefs and freedom are the tender ground. Differences in faith or a fear of being tied down can divide you; a shared sense of meaning unites you.",
+    "Capricorn":   "The wound concerns responsibility and control. One may feel burdened or judged by the other; shared goals built patiently heal it.",
+    "Aquarius":    "The couple may feel like outsiders, or one may keep the other at a distance. Accepting each other's strangeness is the healing.",
+    "Pisces":      "Boundaries blur. Rescuing, sacrifice or escapism can wound both; compassion that keeps two separate selves is the cure.",
+}
+
+// relationshipHouseTexts describe where in shared life the wound shows.
+var relationshipHouseTexts = map[int]string{
+    1:  "It shows in how the couple presents itself: others sense the wound in you as a pair.",
+    2:  "It shows in shared resources and values; how you spend together reveals it.",
+    3:  "It shows in everyday talk, siblings and the small exchanges of the day.",
+    4:  "It shows at home and around family, where the relationship feels safest and most exposed.",
+    5:  "It shows in romance, play and any children or creative projects you share.",
+    6:  "It shows in routines, health and the daily work of the partnership.",
+    7:  "It shows in commitment itself and in how you face the outside world as partners.",
+    8:  "It shows in intimacy, shared money and the crises you go through together.",
+    9:  "It shows in beliefs, travel and the philosophy you build together.",
+    10: "It shows publicly: in shared ambitions and in how the world sees you as a couple.",
+    11: "It shows among friends and in the future you hope for together.",
+    12: "It shows in private: unspoken patterns and what you each keep hidden.",
+}
+
+// midpoint returns the nearer midpoint of two longitudes.
+func midpoint(a, b float64) float64 {
+    return math.Mod(a+norm180(b-a)/2+360, 360)
+}
+
+func relationshipChiron(method string, lon float64, p placement, lat, geoLon float64) RelationshipChiron {
+    sign := signFromLongitude(lon)
+    return RelationshipChiron{
+        Method:           method,
+        Sign:             sign,
+        Degree:           math.Round(math.Mod(lon, 30)*100) / 100,
+        House:            p.House,
+        Ascendant:        round6(p.Asc),
+        RelationshipSign: relationshipSignTexts[sign],
+        RelationshipHome: relationshipHouseTexts[p.House],
+        Lat:              round6(lat),
+        Lon:              round6(geoLon),
+    }
+}
+
+// relationshipPerson validates one partner's birth data and returns its moment.
+func relationshipPerson(req BirthData) (float64, error) {
+    if req.UnknownTime {
+        return 0, errors.New("relationship charts need both birth times")
+    }
+    if req.Topocentric || req.Heliocentric {
+        return 0, errors.New("relationship charts are geocentric")
+    }
+    utc, _, err := birthTime(req)
+    if err != nil {
+        return 0, err
+    }
+    return julianDay(utc), nil
+}
+
+func relationshipHandler(w http.ResponseWriter, r *http.Request) {
+    var req relationshipRequest
+    if err := decodeJSON(w, r, &req); err != nil {
+        writeDecodeError(w, err)
+        return
+    }
+    jdA, err := relationshipPerson(req.PersonA)
+    if err != nil {
+        http.Error(w, "person_a: "+err.Error(), http.StatusBadRequest)
+        return
+    }
+    jdB, err := relationshipPerson(req.PersonB)
+    if err != nil {
+        http.Error(w, "person_b: "+err.Error(), http.StatusBadRequest)
+        return
+    }
+    if req.PersonA.Zodiac != req.PersonB.Zodiac || req.PersonA.Ayanamsa != req.PersonB.Ayanamsa {
+        http.Error(w, "both charts must use the same zodiac and ayanamsa", http.StatusBadRequest)
+        return
+    }
+    opts, err := resolveCalcOptions(req.PersonA)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    resp, err := relationshipCharts(r.Context(), req.PersonA, req.PersonB, jdA, jdB, opts)
+    if err != nil {
+        http.Error(w, "relationship chart calculation failed", http.StatusInternalServerError)
+        return
+    }
+    writeJSON(w, http.StatusOK, resp)
+}
+
+// relationshipCharts computes the composite and Davison charts.
+func relationshipCharts(ctx context.Context, a, b BirthData, jdA, jdB float64, opts calcOptions) (RelationshipReading, error) {
+    ephStart := time.Now()
+    chironA, err := computeChironLongitude(jdA, opts)
+    var chironB float64
+    if err == nil {
+        chironB, err = computeChironLongitude(jdB, opts)
+    }
+    observeEphemeris(ctx, "chiron", ephStart)
+    if err != nil {
+        return RelationshipReading{}, err
+    }
+    natalA, err := placeInHouses(ctx, jdA, a.Lat, a.Lon, chironA, opts)
+    if err != nil {
+        return RelationshipReading{}, err
+    }
+    natalB, err := placeInHouses(ctx, jdB, b.Lat, b.Lon, chironB, opts)
+    if err != nil {
+        return RelationshipReading{}, err
+    }
+
+    // Composite: midpoints of the positions, houses from the midpoint MC
+    // at the mean latitude (the derived-MC method)
+    midJD := (jdA + jdB) / 2
+    midLat := (a.Lat + b.Lat) / 2
+    midLon := norm180(a.Lon + norm180(b.Lon-a.Lon)/2)
+    compChiron := midpoint(chironA, chironB)
+    compMC := midpoint(natalA.MC, natalB.MC)
+    ayanamsa, err := computeAyanamsa(midJD, opts)
+    if err != nil {
+        return RelationshipReading{}, err
+    }
+    eps, err := computeObliquity(midJD)
+    if err != nil {
+        return RelationshipReading{}, err
+    }
+    const rad = math.Pi / 180
+    mcTropical := (compMC + ayanamsa) * rad
+    armc := math.Mod(math.Atan2(math.Sin(mcTropical)*math.Cos(eps*rad), math.Cos(mcTropical))/rad+360, 360)
+    comp, err := placeFromARMC(midJD, armc, midLat, compChiron, opts)
+    if err != nil {
+        return RelationshipReading{}, err
+    }
+
+    // Davison: a real chart for the midpoint in time and space
+    ephStart = time.Now()
+    davChiron, err := computeChironLongitude(midJD, opts)
+    observeEphemeris(ctx, "chiron", ephStart)
+    if err != nil {
+        return RelationshipReading{}, err
+    }
+    dav, err := placeInHouses(ctx, midJD, midLat, midLon, davChiron, opts)
+    if err != nil {
+        return RelationshipReading{}, err
+    }
+
+    resp := RelationshipReading{
+        Composite: relationshipChiron("composite", compChiron, comp, midLat, midLon),
+        Davison:   relationshipChiron("davison", davChiron, dav, midLat, midLon),
+        Zodiac:    opts.Zodiac,
+        Ayanamsa:  opts.Ayanamsa,
+    }
+    resp.Davison.Moment = jdToTime(midJD).Format(time.RFC3339)
+    return resp, nil
+}
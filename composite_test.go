@@ -0,0 +1,24 @@
+package main
+
+import (
+    "math"
+    "testing"
+)
+
+func TestMidpointWraparound(t *testing.T) {
+    for _, c := range []struct{ a, b, want float64 }{
+        {10, 20, 15},
+        {20, 10, 15},
+        {350, 10, 0},
+        {10, 350, 0},
+        {340, 0, 350},
+        {0, 340, 350},
+        {170, 190, 180},
+        {359.5, 0.5, 0},
+        {100, 100, 100},
+    } {
+        if got := midpoint(c.a, c.b); math.Abs(norm180(got-c.want)) > 1e-9 || got < 0 || got >= 360 {
+            t.Errorf("midpoint(%v, %v) = %v, want %v", c.a, c.b, got, c.want)
+        }
+    }
+}
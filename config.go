@@ -0,0 +1,463 @@
+package main
+
+import (
+    "bytes"
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "net"
+    "os"
+    "slices"
+    "strconv"
+    "strings"
+    "time"
+
+    "gopkg.in/yaml.v3"
+)
+
+// ===== Configuration =====
+//
+// Settings are layered: built-in defaults, then the YAML config file, then
+// environment variables, then command-line flags.
+
+type config struct {
+    Server          serverConfig          `yaml:"server"`
+    TLS             tlsConfig             `yaml:"tls"`
+    Ephemeris       ephemerisConfig       `yaml:"ephemeris"`
+    Interpretations interpretationsConfig `yaml:"interpretations"`
+    CORS            corsConfig            `yaml:"cors"`
+    RateLimit       rateLimitConfig       `yaml:"rate_limit"`
+    Log             logConfig             `yaml:"log"`
+}
+
+type ephemerisConfig struct {
+    Backend          string  `yaml:"backend"`           // auto, swiss, moshier, jpl, approx or fake
+    JPLFile          string  `yaml:"jpl_file"`          // JPL DE file in path, for the jpl backend
+    Compare          string  `yaml:"compare"`           // second backend to check every call against; empty turns it off
+    CompareTolerance float64 `yaml:"compare_tolerance"` // arcseconds; larger discrepancies are logged
+
+    Path        string `yaml:"path"`         // directory holding the .se1 files
+    HouseSystem string `yaml:"house_system"` // default when a request doesn't pick one
+    Zodiac      string `yaml:"zodiac"`
+    Ayanamsa    string `yaml:"ayanamsa"` // default for sidereal readings
+
+    StationWindowDays float64 `yaml:"station_window_days"` // Chiron counts as stationary this close to a station
+    AngleOrb          float64 `yaml:"angle_orb"`           // degrees for Chiron conjunct Asc/DC/MC/IC
+    CuspOrb           float64 `yaml:"cusp_orb"`            // degrees before the next house cusp that count as its influence
+    CohortStartYear   int     `yaml:"cohort_start_year"`   // range /api/cohorts precomputes
+    CohortEndYear     int     `yaml:"cohort_end_year"`
+    ChironTable       bool    `yaml:"chiron_table"` // answer tropical geocentric Chiron from the embedded table, 1800-2200
+}
+
+type interpretationsConfig struct {
+    Path string `yaml:"path"` // JSON corpus replacing the built-in texts; empty uses the built-in set
+
+    Layers      []string `yaml:"layers,flow"`  // degree-level layers: decan, terms, critical, sabian
+    DecanRulers string   `yaml:"decan_rulers"` // triplicity or chaldean
+    SabianPath  string   `yaml:"sabian_path"`  // JSON of Sabian symbol texts, required by the sabian layer
+}
+
+type rateLimitConfig struct {
+    RequestsPerSecond float64 `yaml:"requests_per_second"` // per client IP; 0 disables limiting
+    Burst             int     `yaml:"burst"`
+
+    TrustedProxies []string `yaml:"trusted_proxies,flow"` // IPs or CIDRs whose X-Forwarded-For and CF-Connecting-IP are believed
+}
+
+type logConfig struct {
+    Level     string `yaml:"level"`
+    BirthData bool   `yaml:"birth_data"` // log birth time/place instead of redacting it
+}
+
+func defaultConfig() config {
+    return config{
+        Server:    defaultServerConfig(),
+        TLS:       defaultTLSConfig(),
+        Ephemeris: ephemerisConfig{Backend: "auto", CompareTolerance: 1, HouseSystem: "whole_sign", Zodiac: "tropical", Ayanamsa: "lahiri", StationWindowDays: 5, AngleOrb: 5, CuspOrb: 2, CohortStartYear: 1800, CohortEndYear: 2200, ChironTable: true},
+        Interpretations: interpretationsConfig{
+            Layers:      []string{"decan", "terms", "critical"},
+            DecanRulers: "triplicity",
+        },
+        CORS:      defaultCORSConfig(),
+        RateLimit: rateLimitConfig{Burst: 20},
+        Log:       logConfig{Level: "info"},
+    }
+}
+
+// appConfig is the effective configuration, set once at startup.
+var appConfig = defaultConfig()
+
+// houseSystems maps config names to Swiss Ephemeris house system codes.
+var houseSystems = map[string]byte{
+    "whole_sign":    'W',
+    "placidus":      'P',
+    "koch":          'K',
+    "equal":         'E',
+    "porphyry":      'O',
+    "regiomontanus": 'R',
+    "campanus":      'C',
+    "alcabitius":    'B',
+    "morinus":       'M',
+    "topocentric":   'T',
+}
+
+var zodiacs = map[string]bool{"tropical": true, "sidereal": true}
+
+// ===== Loading =====
+
+// loadConfigFile merges a YAML file over cfg. Unknown keys are rejected so
+// typos don't silently fall back to defaults.
+func loadConfigFile(path string, cfg *config) error {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return err
+    }
+    dec := yaml.NewDecoder(bytes.NewReader(data))
+    dec.KnownFields(true)
+    if err := dec.Decode(cfg); err != nil && err != io.EOF {
+        return fmt.Errorf("%s: %w", path, err)
+    }
+    return nil
+}
+
+type envBinding struct {
+    name string
+    set  func(cfg *config, v string) error
+}
+
+func setString(p func(*config) *string) func(*config, string) error {
+    return func(cfg *config, v string) error { *p(cfg) = v; return nil }
+}
+
+func setList(p func(*config) *[]string) func(*config, string) error {
+    return func(cfg *config, v string) error { *p(cfg) = splitList(v); return nil }
+}
+
+func setInt(p func(*config) *int) func(*config, string) error {
+    return func(cfg *config, v string) error {
+        n, err := strconv.Atoi(v)
+        *p(cfg) = n
+        return err
+    }
+}
+
+func setInt64(p func(*config) *int64) func(*config, string) error {
+    return func(cfg *config, v string) error {
+        n, err := strconv.ParseInt(v, 10, 64)
+        *p(cfg) = n
+        return err
+    }
+}
+
+func setFloat(p func(*config) *float64) func(*config, string) error {
+    return func(cfg *config, v string) error {
+        f, err := strconv.ParseFloat(v, 64)
+        *p(cfg) = f
+        return err
+    }
+}
+
+func setBool(p func(*config) *bool) func(*config, string) error {
+    return func(cfg *config, v string) error {
+        b, err := strconv.ParseBool(v)
+        *p(cfg) = b
+        return err
+    }
+}
+
+func setDuration(p func(*config) *time.Duration) func(*config, string) error {
+    return func(cfg *config, v string) error {
+        d, err := time.ParseDuration(v)
+        *p(cfg) = d
+        return err
+    }
+}
+
+var envBindings = []envBinding{
+    {"PORT", setInt(func(c *config) *int { return &c.Server.Port })},
+    {"BIND_ADDRESS", setString(func(c *config) *string { return &c.Server.BindAddress })},
+    {"HTTP_READ_HEADER_TIMEOUT", setDuration(func(c *config) *time.Duration { return &c.Server.ReadHeaderTimeout })},
+    {"HTTP_READ_TIMEOUT", setDuration(func(c *config) *time.Duration { return &c.Server.ReadTimeout })},
+    {"HTTP_WRITE_TIMEOUT", setDuration(func(c *config) *time.Duration { return &c.Server.WriteTimeout })},
+    {"HTTP_IDLE_TIMEOUT", setDuration(func(c *config) *time.Duration { return &c.Server.IdleTimeout })},
+    {"SHUTDOWN_DELAY", setDuration(func(c *config) *time.Duration { return &c.Server.ShutdownDelay })},
+    {"SHUTDOWN_GRACE_PERIOD", setDuration(func(c *config) *time.Duration { return &c.Server.ShutdownGrace })},
+    {"MAX_BODY_BYTES", setInt64(func(c *config) *int64 { return &c.Server.MaxBodyBytes })},
+    {"TLS_CERT_FILE", setString(func(c *config) *string { return &c.TLS.CertFile })},
+    {"TLS_KEY_FILE", setString(func(c *config) *string { return &c.TLS.KeyFile })},
+    {"TLS_CLIENT_CA_FILE", setString(func(c *config) *string { return &c.TLS.ClientCAFile })},
+    {"TLS_CLIENT_AUTH", setString(func(c *config) *string { return &c.TLS.ClientAuth })},
+    {"TLS_MIN_VERSION", setString(func(c *config) *string { return &c.TLS.MinVersion })},
+    {"TLS_RELOAD_INTERVAL", setDuration(func(c *config) *time.Duration { return &c.TLS.ReloadInterval })},
+    {"TLS_REDIRECT_HTTP_PORT", setInt(func(c *config) *int { return &c.TLS.RedirectHTTPPort })},
+    {"EPHEMERIS_BACKEND", setString(func(c *config) *string { return &c.Ephemeris.Backend })},
+    {"EPHEMERIS_JPL_FILE", setString(func(c *config) *string { return &c.Ephemeris.JPLFile })},
+    {"EPHEMERIS_COMPARE", setString(func(c *config) *string { return &c.Ephemeris.Compare })},
+    {"EPHEMERIS_COMPARE_TOLERANCE", setFloat(func(c *config) *float64 { return &c.Ephemeris.CompareTolerance })},
+    {"SE_EPHE_PATH", setString(func(c *config) *string { return &c.Ephemeris.Path })},
+    {"HOUSE_SYSTEM", setString(func(c *config) *string { return &c.Ephemeris.HouseSystem })},
+    {"ZODIAC", setString(func(c *config) *string { return &c.Ephemeris.Zodiac })},
+    {"AYANAMSA", setString(func(c *config) *string { return &c.Ephemeris.Ayanamsa })},
+    {"STATION_WINDOW_DAYS", setFloat(func(c *config) *float64 { return &c.Ephemeris.StationWindowDays })},
+    {"ANGLE_ORB", setFloat(func(c *config) *float64 { return &c.Ephemeris.AngleOrb })},
+    {"CUSP_ORB", setFloat(func(c *config) *float64 { return &c.Ephemeris.CuspOrb })},
+    {"COHORT_START_YEAR", setInt(func(c *config) *int { return &c.Ephemeris.CohortStartYear })},
+    {"COHORT_END_YEAR", setInt(func(c *config) *int { return &c.Ephemeris.CohortEndYear })},
+    {"CHIRON_TABLE", setBool(func(c *config) *bool { return &c.Ephemeris.ChironTable })},
+    {"INTERPRETATIONS_PATH", setString(func(c *config) *string { return &c.Interpretations.Path })},
+    {"INTERPRETATION_LAYERS", setList(func(c *config) *[]string { return &c.Interpretations.Layers })},
+    {"DECAN_RULERS", setString(func(c *config) *string { return &c.Interpretations.DecanRulers })},
+    {"SABIAN_PATH", setString(func(c *config) *string { return &c.Interpretations.SabianPath })},
+    {"CORS_ALLOWED_ORIGINS", setList(func(c *config) *[]string { return &c.CORS.AllowedOrigins })},
+    {"CORS_ALLOWED_METHODS", setList(func(c *config) *[]string { return &c.CORS.AllowedMethods })},
+    {"CORS_ALLOWED_HEADERS", setList(func(c *config) *[]string { return &c.CORS.AllowedHeaders })},
+    {"CORS_ALLOW_CREDENTIALS", setBool(func(c *config) *bool { return &c.CORS.AllowCredentials })},
+    {"CORS_MAX_AGE", setInt(func(c *config) *int { return &c.CORS.MaxAge })},
+    {"RATE_LIMIT_RPS", setFloat(func(c *config) *float64 { return &c.RateLimit.RequestsPerSecond })},
+    {"RATE_LIMIT_BURST", setInt(func(c *config) *int { return &c.RateLimit.Burst })},
+    {"TRUSTED_PROXIES", setList(func(c *config) *[]string { return &c.RateLimit.TrustedProxies })},
+    {"LOG_LEVEL", setString(func(c *config) *string { return &c.Log.Level })},
+    {"LOG_BIRTH_DATA", setBool(func(c *config) *bool { return &c.Log.BirthData })},
+}
+
+func applyEnv(cfg *config, getenv func(string) string) error {
+    var errs []error
+    for _, b := range envBindings {
+        if v := getenv(b.name); v != "" {
+            if err := b.set(cfg, v); err != nil {
+                errs = append(errs, fmt.Errorf("%s: %w", b.name, err))
+            }
+        }
+    }
+    return errors.Join(errs...)
+}
+
+type listFlag struct{ p *[]string }
+
+func (l listFlag) String() string {
+    if l.p == nil {
+        return ""
+    }
+    return strings.Join(*l.p, ",")
+}
+
+func (l listFlag) Set(v string) error {
+    *l.p = splitList(v)
+    return nil
+}
+
+// newConfigFlagSet binds command-line flags directly onto cfg.
+func newConfigFlagSet(name string, cfg *config, configPath *string) *flag.FlagSet {
+    fs := flag.NewFlagSet(name, flag.ContinueOnError)
+    fs.StringVar(configPath, "config", *configPath, "path to a YAML config file (env CHIRON_CONFIG)")
+    fs.IntVar(&cfg.Server.Port, "port", cfg.Server.Port, "HTTP listen port")
+    fs.StringVar(&cfg.Server.BindAddress, "bind", cfg.Server.BindAddress, "address to bind, empty for all interfaces")
+    fs.DurationVar(&cfg.Server.ShutdownDelay, "shutdown-delay", cfg.Server.ShutdownDelay, "time /readyz fails before listeners close on shutdown")
+    fs.DurationVar(&cfg.Server.ShutdownGrace, "shutdown-grace", cfg.Server.ShutdownGrace, "time in-flight requests get to finish on shutdown")
+    fs.StringVar(&cfg.TLS.CertFile, "tls-cert", cfg.TLS.CertFile, "TLS certificate file")
+    fs.StringVar(&cfg.TLS.KeyFile, "tls-key", cfg.TLS.KeyFile, "TLS private key file")
+    fs.StringVar(&cfg.TLS.ClientCAFile, "tls-client-ca", cfg.TLS.ClientCAFile, "CA bundle for client certificates")
+    fs.StringVar(&cfg.TLS.ClientAuth, "tls-client-auth", cfg.TLS.ClientAuth, "client certificates: none, optional or require")
+    fs.IntVar(&cfg.TLS.RedirectHTTPPort, "tls-redirect-port", cfg.TLS.RedirectHTTPPort, "plain HTTP port redirecting to HTTPS, 0 disables")
+    fs.StringVar(&cfg.Ephemeris.Backend, "ephemeris", cfg.Ephemeris.Backend, "ephemeris backend: auto, swiss, moshier, jpl, approx or fake")
+    fs.StringVar(&cfg.Ephemeris.Compare, "ephemeris-compare", cfg.Ephemeris.Compare, "second ephemeris backend to compare every call against")
+    fs.StringVar(&cfg.Ephemeris.Path, "ephe-path", cfg.Ephemeris.Path, "directory with Swiss Ephemeris .se1 files")
+    fs.StringVar(&cfg.Ephemeris.HouseSystem, "house-system", cfg.Ephemeris.HouseSystem, "default house system")
+    fs.StringVar(&cfg.Ephemeris.Zodiac, "zodiac", cfg.Ephemeris.Zodiac, "default zodiac: tropical or sidereal")
+    fs.StringVar(&cfg.Ephemeris.Ayanamsa, "ayanamsa", cfg.Ephemeris.Ayanamsa, "default ayanamsa for sidereal readings")
+    fs.Float64Var(&cfg.Ephemeris.AngleOrb, "angle-orb", cfg.Ephemeris.AngleOrb, "orb in degrees for Chiron conjunct an angle")
+    fs.Float64Var(&cfg.Ephemeris.CuspOrb, "cusp-orb", cfg.Ephemeris.CuspOrb, "degrees before a house cusp that count as its influence")
+    fs.StringVar(&cfg.Interpretations.Path, "interpretations", cfg.Interpretations.Path, "JSON interpretation corpus")
+    fs.Var(listFlag{&cfg.Interpretations.Layers}, "layers", "comma-separated degree layers: decan, terms, critical, sabian")
+    fs.StringVar(&cfg.Interpretations.SabianPath, "sabian", cfg.Interpretations.SabianPath, "JSON Sabian symbol texts")
+    fs.Var(listFlag{&cfg.CORS.AllowedOrigins}, "cors-origins", "comma-separated CORS allowed origins")
+    fs.Float64Var(&cfg.RateLimit.RequestsPerSecond, "rate-limit-rps", cfg.RateLimit.RequestsPerSecond, "per-client API requests per second, 0 disables")
+    fs.IntVar(&cfg.RateLimit.Burst, "rate-limit-burst", cfg.RateLimit.Burst, "per-client burst size")
+    fs.Var(listFlag{&cfg.RateLimit.TrustedProxies}, "trusted-proxies", "comma-separated proxy IPs or CIDRs whose forwarded client addresses are used")
+    fs.StringVar(&cfg.Log.Level, "log-level", cfg.Log.Level, "debug, info, warn or error")
+    return fs
+}
+
+// resolveConfig layers the config file (-config or CHIRON_CONFIG), the
+// environment and args over the defaults, without validating the result.
+func resolveConfig(args []string) (config, error) {
+    // First pass only finds the config file; flags are applied again on top
+    // of the file and environment below.
+    configPath := os.Getenv("CHIRON_CONFIG")
+    scratch := defaultConfig()
+    if err := newConfigFlagSet("chiron-oracle", &scratch, &configPath).Parse(args); err != nil {
+        return config{}, err
+    }
+
+    cfg := defaultConfig()
+    if configPath != "" {
+        if err := loadConfigFile(configPath, &cfg); err != nil {
+            return config{}, err
+        }
+    }
+    if err := applyEnv(&cfg, os.Getenv); err != nil {
+        return config{}, err
+    }
+    fs := newConfigFlagSet("chiron-oracle", &cfg, &configPath)
+    fs.SetOutput(io.Discard) // usage was already printed by the first pass
+    if err := fs.Parse(args); err != nil {
+        return config{}, err
+    }
+    return cfg, nil
+}
+
+// loadConfig resolves and validates the effective configuration.
+func loadConfig(args []string) (config, error) {
+    cfg, err := resolveConfig(args)
+    if err != nil {
+        return cfg, err
+    }
+    return cfg, cfg.validate()
+}
+
+func (c config) validate() error {
+    var errs []error
+    if c.Server.Port < 1 || c.Server.Port > 65535 {
+        errs = append(errs, fmt.Errorf("server.port %d out of range", c.Server.Port))
+    }
+    if c.Server.BindAddress != "" && net.ParseIP(c.Server.BindAddress) == nil && c.Server.BindAddress != "localhost" {
+        errs = append(errs, fmt.Errorf("server.bind_address %q is not an IP address", c.Server.BindAddress))
+    }
+    for name, d := range map[string]time.Duration{
+        "server.read_header_timeout": c.Server.ReadHeaderTimeout,
+        "server.read_timeout":        c.Server.ReadTimeout,
+        "server.write_timeout":       c.Server.WriteTimeout,
+        "server.idle_timeout":        c.Server.IdleTimeout,
+        "server.shutdown_grace":      c.Server.ShutdownGrace,
+    } {
+        if d <= 0 {
+            errs = append(errs, fmt.Errorf("%s must be positive", name))
+        }
+    }
+    if c.Server.ShutdownDelay < 0 {
+        errs = append(errs, errors.New("server.shutdown_delay must not be negative"))
+    }
+    if c.Server.MaxBodyBytes <= 0 {
+        errs = append(errs, errors.New("server.max_body_bytes must be positive"))
+    }
+    errs = append(errs, c.TLS.validate()...)
+    if c.TLS.RedirectHTTPPort != 0 && c.TLS.RedirectHTTPPort == c.Server.Port {
+        errs = append(errs, errors.New("tls.redirect_http_port must differ from server.port"))
+    }
+    for _, p := range []string{c.Interpretations.Path, c.Interpretations.SabianPath} {
+        if p != "" {
+            if _, err := os.Stat(p); err != nil {
+                errs = append(errs, err)
+            }
+        }
+    }
+    for _, l := range c.Interpretations.Layers {
+        if !interpretationLayers[l] {
+            errs = append(errs, fmt.Errorf("interpretations.layers: %q unknown (one of %s)",
+                l, strings.Join(sortedKeys(interpretationLayers), ", ")))
+        }
+    }
+    if slices.Contains(c.Interpretations.Layers, "sabian") && c.Interpretations.SabianPath == "" {
+        // No texts are built in, so the layer would always be empty
+        errs = append(errs, errors.New("interpretations.layers: sabian needs interpretations.sabian_path"))
+    }
+    if !decanRulerSystems[c.Interpretations.DecanRulers] {
+        errs = append(errs, fmt.Errorf("interpretations.decan_rulers %q unknown (triplicity or chaldean)", c.Interpretations.DecanRulers))
+    }
+    if !ephemerisBackends[c.Ephemeris.Backend] {
+        errs = append(errs, fmt.Errorf("ephemeris.backend %q unknown (one of %s)",
+            c.Ephemeris.Backend, strings.Join(sortedKeys(ephemerisBackends), ", ")))
+    }
+    if c.Ephemeris.Compare != "" && (!ephemerisBackends[c.Ephemeris.Compare] || c.Ephemeris.Compare == "auto") {
+        errs = append(errs, fmt.Errorf("ephemeris.compare %q unknown", c.Ephemeris.Compare))
+    }
+    if (c.Ephemeris.Backend == "jpl" || c.Ephemeris.Compare == "jpl") && c.Ephemeris.JPLFile == "" {
+        errs = append(errs, errors.New("ephemeris.jpl_file is required for the jpl backend"))
+    }
+    if c.Ephemeris.CompareTolerance < 0 {
+        errs = append(errs, errors.New("ephemeris.compare_tolerance must not be negative"))
+    }
+    if c.Ephemeris.Path != "" {
+        if fi, err := os.Stat(c.Ephemeris.Path); err != nil {
+            errs = append(errs, err)
+        } else if !fi.IsDir() {
+            errs = append(errs, fmt.Errorf("ephemeris.path %s is not a directory", c.Ephemeris.Path))
+        }
+    }
+    if _, ok := houseSystems[c.Ephemeris.HouseSystem]; !ok {
+        errs = append(errs, fmt.Errorf("ephemeris.house_system %q unknown (one of %s)",
+            c.Ephemeris.HouseSystem, strings.Join(sortedKeys(houseSystems), ", ")))
+    }
+    if !zodiacs[c.Ephemeris.Zodiac] {
+        errs = append(errs, fmt.Errorf("ephemeris.zodiac %q unknown (one of %s)",
+            c.Ephemeris.Zodiac, strings.Join(sortedKeys(zodiacs), ", ")))
+    }
+    if _, ok := ayanamsas[c.Ephemeris.Ayanamsa]; !ok {
+        errs = append(errs, fmt.Errorf("ephemeris.ayanamsa %q unknown, see /api/ayanamsas", c.Ephemeris.Ayanamsa))
+    }
+    if c.Ephemeris.StationWindowDays < 0 {
+        errs = append(errs, errors.New("ephemeris.station_window_days must not be negative"))
+    }
+    if c.Ephemeris.AngleOrb < 0 || c.Ephemeris.AngleOrb > 15 {
+        errs = append(errs, fmt.Errorf("ephemeris.angle_orb %g out of range (0-15)", c.Ephemeris.AngleOrb))
+    }
+    if c.Ephemeris.CuspOrb < 0 || c.Ephemeris.CuspOrb > 10 {
+        errs = append(errs, fmt.Errorf("ephemeris.cusp_orb %g out of range (0-10)", c.Ephemeris.CuspOrb))
+    }
+    // Swiss Ephemeris only has Chiron between 675 and 4650 AD
+    if c.Ephemeris.CohortStartYear < 700 || c.Ephemeris.CohortEndYear > 4600 ||
+        c.Ephemeris.CohortStartYear >= c.Ephemeris.CohortEndYear {
+        errs = append(errs, fmt.Errorf("ephemeris.cohort_start_year/cohort_end_year %d-%d out of range (700-4600)",
+            c.Ephemeris.CohortStartYear, c.Ephemeris.CohortEndYear))
+    }
+    if len(c.CORS.AllowedOrigins) == 0 {
+        errs = append(errs, errors.New("cors.allowed_origins must not be empty"))
+    }
+    if c.CORS.AllowCredentials && slices.Contains(c.CORS.AllowedOrigins, "*") {
+        // Credentialed reads would then be open to every site
+        errs = append(errs, errors.New(`cors.allow_credentials needs explicit cors.allowed_origins, not "*"`))
+    }
+    if c.RateLimit.RequestsPerSecond < 0 || c.RateLimit.Burst < 0 {
+        errs = append(errs, errors.New("rate_limit values must not be negative"))
+    }
+    if c.RateLimit.RequestsPerSecond > 0 && c.RateLimit.Burst < 1 {
+        errs = append(errs, errors.New("rate_limit.burst must be at least 1 when limiting is enabled"))
+    }
+    if _, err := parseTrustedProxies(c.RateLimit.TrustedProxies); err != nil {
+        errs = append(errs, fmt.Errorf("rate_limit.trusted_proxies: %w", err))
+    }
+    switch strings.ToLower(c.Log.Level) {
+    case "debug", "info", "warn", "warning", "error":
+    default:
+        errs = append(errs, fmt.Errorf("log.level %q unknown", c.Log.Level))
+    }
+    return errors.Join(errs...)
+}
+
+// configCommand implements `chiron-oracle config print [flags]`, dumping the
+// effective configuration as YAML.
+func configCommand(args []string) int {
+    if len(args) == 0 || args[0] != "print" {
+        fmt.Fprintln(os.Stderr, "usage: chiron-oracle config print [flags]")
+        return 2
+    }
+    cfg, err := resolveConfig(args[1:])
+    if err != nil {
+        if !errors.Is(err, flag.ErrHelp) {
+            fmt.Fprintln(os.Stderr, err)
+        }
+        return 2
+    }
+    enc := yaml.NewEncoder(os.Stdout)
+    enc.SetIndent(2)
+    if err := enc.Encode(cfg); err != nil {
+        fmt.Fprintln(os.Stderr, err)
+        return 1
+    }
+    if err := cfg.validate(); err != nil {
+        fmt.Fprintln(os.Stderr, "invalid configuration:", err)
+        return 1
+    }
+    return 0
+}
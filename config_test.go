@@ -0,0 +1,49 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestResolveConfigPrecedence(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "config.yaml")
+    yaml := "server:\n  port: 9000\nephemeris:\n  zodiac: sidereal\n  angle_orb: 3\nrate_limit:\n  burst: 5\n"
+    if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    for _, name := range []string{"HOUSE_SYSTEM", "ZODIAC", "ANGLE_ORB"} {
+        t.Setenv(name, "") // empty counts as unset
+    }
+    t.Setenv("CHIRON_CONFIG", path)
+    t.Setenv("PORT", "9100")
+    t.Setenv("RATE_LIMIT_BURST", "7")
+    t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8")
+
+    cfg, err := resolveConfig([]string{"-port", "9200", "-trusted-proxies", "192.0.2.1,198.51.100.0/24"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    def := defaultConfig()
+    for _, c := range []struct {
+        name      string
+        got, want interface{}
+    }{
+        {"default house system", cfg.Ephemeris.HouseSystem, def.Ephemeris.HouseSystem},
+        {"YAML over default zodiac", cfg.Ephemeris.Zodiac, "sidereal"},
+        {"YAML over default angle orb", cfg.Ephemeris.AngleOrb, 3.0},
+        {"env over YAML burst", cfg.RateLimit.Burst, 7},
+        {"flag over env and YAML port", cfg.Server.Port, 9200},
+        {"flag over env list", len(cfg.RateLimit.TrustedProxies), 2},
+    } {
+        if c.got != c.want {
+            t.Errorf("%s: %v, want %v", c.name, c.got, c.want)
+        }
+    }
+
+    cfg.RateLimit.TrustedProxies = []string{"proxy.internal"}
+    if err := cfg.validate(); err == nil || !strings.Contains(err.Error(), "trusted_proxies") {
+        t.Error("a host name accepted in rate_limit.trusted_proxies")
+    }
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+    "net/http"
+    "strconv"
+    "strings"
+)
+
+// ===== CORS =====
+
+type corsConfig struct {
+    AllowedOrigins   []string `yaml:"allowed_origins,flow"`
+    AllowedMethods   []string `yaml:"allowed_methods,flow"`
+    AllowedHeaders   []string `yaml:"allowed_headers,flow"`
+    AllowCredentials bool     `yaml:"allow_credentials"`
+    MaxAge           int      `yaml:"max_age"` // seconds a preflight may be cached
+}
+
+func defaultCORSConfig() corsConfig {
+    return corsConfig{
+        AllowedOrigins: []string{"*"},
+        AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+        AllowedHeaders: []string{"Content-Type", "Authorization"},
+        MaxAge:         600,
+    }
+}
+
+func splitList(s string) []string {
+    var out []string
+    for _, part := range strings.Split(s, ",") {
+        if part = strings.TrimSpace(part); part != "" {
+            out = append(out, part)
+        }
+    }
+    return out
+}
+
+func (c corsConfig) allowOrigin(origin string) (string, bool) {
+    for _, o := range c.AllowedOrigins {
+        if o == "*" {
+            return "*", true
+        }
+        if strings.EqualFold(o, origin) {
+            return origin, true
+        }
+    }
+    return "", false
+}
+
+// corsMiddleware adds Access-Control-* headers to /api/* responses and
+// answers preflight requests itself. Other routes are passed through untouched.
+func corsMiddleware(cfg corsConfig, next http.Handler) http.Handler {
+    methods := strings.Join(cfg.AllowedMethods, ", ")
+    headers := strings.Join(cfg.AllowedHeaders, ", ")
+
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        origin := r.Header.Get("Origin")
+        if origin == "" || !strings.HasPrefix(r.URL.Path, "/api/") {
+            next.ServeHTTP(w, r)
+            return
+        }
+
+        w.Header().Add("Vary", "Origin")
+        allowed, ok := cfg.allowOrigin(origin)
+        preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+        if !ok {
+            if preflight {
+                w.WriteHeader(http.StatusForbidden)
+                return
+            }
+            next.ServeHTTP(w, r)
+            return
+        }
+
+        w.Header().Set("Access-Control-Allow-Origin", allowed)
+        // Browsers refuse credentials with a wildcard origin anyway
+        if cfg.AllowCredentials && allowed != "*" {
+            w.Header().Set("Access-Control-Allow-Credentials", "true")
+        }
+
+        if preflight {
+            w.Header().Add("Vary", "Access-Control-Request-Method")
+            w.Header().Add("Vary", "Access-Control-Request-Headers")
+            w.Header().Set("Access-Control-Allow-Methods", methods)
+            w.Header().Set("Access-Control-Allow-Headers", headers)
+            if cfg.MaxAge > 0 {
+                w.Header().Set("Access-Control-Max-Age", strconv.Itoa(cfg.MaxAge))
+            }
+            w.WriteHeader(http.StatusNoContent)
+            return
+        }
+
+        next.ServeHTTP(w, r)
+    })
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+func TestCORSWildcardWithCredentialsRejected(t *testing.T) {
+    cfg := defaultConfig()
+    cfg.CORS.AllowCredentials = true
+    if err := cfg.validate(); err == nil {
+        t.Error(`allow_credentials with "*" passed validation`)
+    }
+    cfg.CORS.AllowedOrigins = []string{"https://oracle.example"}
+    if err := cfg.validate(); err != nil {
+        t.Errorf("allow_credentials with an explicit origin: %v", err)
+    }
+}
+
+func TestCORSWildcardNeverSendsCredentials(t *testing.T) {
+    cfg := defaultCORSConfig()
+    cfg.AllowCredentials = true // as if validation had been skipped
+    h := corsMiddleware(cfg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+    r := httptest.NewRequest(http.MethodGet, "/api/ayanamsas", nil)
+    r.Header.Set("Origin", "https://evil.example")
+    w := httptest.NewRecorder()
+    h.ServeHTTP(w, r)
+    if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+        t.Errorf("Access-Control-Allow-Origin %q, want *", got)
+    }
+    if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+        t.Errorf("Access-Control-Allow-Credentials %q with a wildcard origin", got)
+    }
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+    "encoding/csv"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "log/slog"
+    "math"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// ===== Ephemeris tables =====
+
+const (
+    ephemerisMaxRows   = 200000 // about 550 years at one row a day
+    ephemerisChunkRows = 500    // rows computed per ephemeris lock, then flushed
+    ephemerisChunkTime = 30 * time.Second
+    ephemerisMaxStep   = 36525 * 24 * time.Hour // a century; keeps n*unit clear of overflow
+)
+
+// ephemerisBodies are the bodies /api/ephemeris can tabulate.
+var ephemerisBodies = func() map[string]int {
+    m := map[string]int{"chiron": SE_CHIRON}
+    for _, b := range chartBodies {
+        m[strings.ToLower(b.Name)] = b.ID
+    }
+    return m
+}()
+
+type EphemerisRow struct {
+    Date        string  `json:"date"` // UTC, RFC 3339
+    Longitude   float64 `json:"longitude"`
+    Speed       float64 `json:"speed"` // degrees per day
+    Sign        string  `json:"sign"`
+    Degree      float64 `json:"degree"`
+    Declination float64 `json:"declination"`
+    Retrograde  bool    `json:"retrograde"`
+
+    t time.Time
+}
+
+// ephemerisRows computes one row per time in a single ephemeris lock.
+func ephemerisRows(times []time.Time, body int, opts calcOptions) ([]EphemerisRow, error) {
+    rows := make([]EphemerisRow, 0, len(times))
+    ecl := make([]float64, 6)
+    equ := make([]float64, 6)
+    var err error
+    withEphemeris(opts, func() {
+        for _, t := range times {
+            jd := julianDay(t)
+            if err = eph.CalcUt(jd, body, opts.flags(), ecl); err != nil {
+                return
+            }
+            if err = eph.CalcUt(jd, body, (opts.flags()&^SEFLG_SIDEREAL)|SEFLG_EQUATORIAL, equ); err != nil {
+                return
+            }
+            rows = append(rows, EphemerisRow{
+                Date:        t.Format(time.RFC3339),
+                Longitude:   round6(ecl[0]),
+                Speed:       round6(ecl[3]),
+                Sign:        signFromLongitude(ecl[0]),
+                Degree:      round6(math.Mod(ecl[0], 30)),
+                Declination: round6(equ[1]),
+                Retrograde:  ecl[3] < 0,
+                t:           t,
+            })
+        }
+    })
+    if err != nil {
+        ephemerisErrors.inc("bodies")
+    }
+    return rows, err
+}
+
+// parseStep reads a step such as 1d, 7d or 12h.
+func parseStep(s string) (time.Duration, error) {
+    if len(s) < 2 {
+        return 0, errors.New("step must look like 1d or 6h")
+    }
+    n, err := strconv.Atoi(s[:len(s)-1])
+    if err != nil || n < 1 {
+        return 0, errors.New("step must look like 1d or 6h")
+    }
+    unit := time.Hour
+    switch s[len(s)-1] {
+    case 'd':
+        unit = 24 * time.Hour
+    case 'h':
+    default:
+        return 0, errors.New("step must look like 1d or 6h")
+    }
+    if n > int(ephemerisMaxStep/unit) {
+        return 0, errors.New("step must be at most a century")
+    }
+    return time.Duration(n) * unit, nil
+}
+
+// ephemerisWriter writes rows in one of the output formats.
+type ephemerisWriter interface {
+    header() error
+    rows([]EphemerisRow) error
+    footer() error
+    cutShort(reason string) error // ends a table that can't be finished
+}
+
+type jsonEphemeris struct {
+    w     io.Writer
+    meta  map[string]string
+    count int
+}
+
+func (j *jsonEphemeris) header() error {
+    meta, _ := json.Marshal(j.meta)
+    // Reopen the metadata object to append the rows
+    _, err := fmt.Fprintf(j.w, "%s,\"rows\":[", meta[:len(meta)-1])
+    return err
+}
+
+func (j *jsonEphemeris) rows(rows []EphemerisRow) error {
+    for _, r := range rows {
+        b, _ := json.Marshal(r)
+        if j.count > 0 {
+            b = append([]byte{','}, b...)
+        }
+        if _, err := j.w.Write(b); err != nil {
+            return err
+        }
+        j.count++
+    }
+    return nil
+}
+
+func (j *jsonEphemeris) footer() error {
+    _, err := io.WriteString(j.w, "]}\n")
+    return err
+}
+
+func (j *jsonEphemeris) cutShort(reason string) error {
+    msg, _ := json.Marshal(reason)
+    _, err := fmt.Fprintf(j.w, "],\"error\":%s}\n", msg)
+    return err
+}
+
+type csvEphemeris struct{ w *csv.Writer }
+
+func (c *csvEphemeris) header() error {
+    return c.w.Write([]string{"date", "longitude", "speed", "sign", "degree", "declination", "retrograde"})
+}
+
+func (c *csvEphemeris) rows(rows []EphemerisRow) error {
+    for _, r := range rows {
+        c.w.Write([]string{
+            r.Date,
+            strconv.FormatFloat(r.Longitude, 'f', 6, 64),
+            strconv.FormatFloat(r.Speed, 'f', 6, 64),
+            r.Sign,
+            strconv.FormatFloat(r.Degree, 'f', 6, 64),
+            strconv.FormatFloat(r.Declination, 'f', 6, 64),
+            strconv.FormatBool(r.Retrograde),
+        })
+    }
+    c.w.Flush()
+    return c.w.Error()
+}
+
+func (c *csvEphemeris) footer() error { return nil }
+
+func (c *csvEphemeris) cutShort(reason string) error {
+    c.w.Write([]string{"error: " + reason})
+    c.w.Flush()
+    return c.w.Error()
+}
+
+// textEphemeris is the classic printed layout: a block per month, positions in
+// degrees, minutes and seconds of the sign, R marking retrograde days.
+type textEphemeris struct {
+    w     io.Writer
+    title string // body and zodiac, e.g. "CHIRON (tropical)"
+    daily bool   // whole-day steps print dates without a time
+    month time.Month
+    year  int
+}
+
+var signAbbrev = map[string]string{
+    "Aries": "Ari", "Taurus": "Tau", "Gemini": "Gem", "Cancer": "Can", "Leo": "Leo", "Virgo": "Vir",
+    "Libra": "Lib", "Scorpio": "Sco", "Sagittarius": "Sag", "Capricorn": "Cap", "Aquarius": "Aqu", "Pisces": "Pis",
+}
+
+// dms formats degrees as 12°34'56".
+func dms(deg float64) string {
+    s := int(math.Round(math.Abs(deg) * 3600))
+    return fmt.Sprintf("%2d°%02d'%02d\"", s/3600, s/60%60, s%60)
+}
+
+func (t *textEphemeris) header() error { return nil }
+
+func (t *textEphemeris) rows(rows []EphemerisRow) error {
+    var b strings.Builder
+    for _, r := range rows {
+        if r.t.Month() != t.month || r.t.Year() != t.year {
+            if t.year != 0 {
+                b.WriteString("\n")
+            }
+            t.month, t.year = r.t.Month(), r.t.Year()
+            fmt.Fprintf(&b, "%s  %s %d  UT\n", t.title, t.month, t.year)
+            fmt.Fprintf(&b, "%-16s  %-15s  %-9s  %s\n", "Date", "Longitude", "Speed", "Decl")
+        }
+        date := r.t.Format("Mon 02")
+        if !t.daily {
+            date = r.t.Format("Mon 02 15:04")
+        }
+        retro := " "
+        if r.Retrograde {
+            retro = "R"
+        }
+        sign := "+"
+        if r.Declination < 0 {
+            sign = "-"
+        }
+        fmt.Fprintf(&b, "%-16s  %s %s %s  %+9.5f  %s%s\n",
+            date, dms(r.Degree), signAbbrev[r.Sign], retro, r.Speed, sign, strings.TrimSpace(dms(r.Declination)))
+    }
+    _, err := io.WriteString(t.w, b.String())
+    return err
+}
+
+func (t *textEphemeris) footer() error { return nil }
+
+func (t *textEphemeris) cutShort(reason string) error {
+    _, err := fmt.Fprintf(t.w, "\n*** %s ***\n", reason)
+    return err
+}
+
+// ephemerisTableHandler serves GET /api/ephemeris: a table of positions from
+// start to end (inclusive, YYYY-MM-DD) at step, as json, csv or text. Rows are
+// computed and flushed in chunks so long ranges stream.
+func ephemerisTableHandler(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+    q := r.URL.Query()
+    name := strings.ToLower(q.Get("body"))
+    if name == "" {
+        name = "chiron"
+    }
+    body, ok := ephemerisBodies[name]
+    if !ok {
+        http.Error(w, fmt.Sprintf("unknown body %q (one of %s)", name, strings.Join(sortedKeys(ephemerisBodies), ", ")), http.StatusBadRequest)
+        return
+    }
+    opts, err := resolveCalcOptions(BirthData{Zodiac: q.Get("zodiac"), Ayanamsa: q.Get("ayanamsa")})
+    if err != nil {
+        slog.WarnContext(r.Context(), "ephemeris table options rejected", append(requestLogAttrs(r.Context()), "err", err)...)
+        http.Error(w, "zodiac or ayanamsa not available", http.StatusBadRequest)
+        return
+    }
+    start, err := time.Parse("2006-01-02", q.Get("start"))
+    var end time.Time
+    if err == nil {
+        end, err = time.Parse("2006-01-02", q.Get("end"))
+    }
+    if err != nil {
+        http.Error(w, "start and end must both be YYYY-MM-DD", http.StatusBadRequest)
+        return
+    }
+    stepText := q.Get("step")
+    if stepText == "" {
+        stepText = "1d"
+    }
+    step, err := parseStep(stepText)
+    if err != nil {
+        slog.WarnContext(r.Context(), "ephemeris table step rejected", append(requestLogAttrs(r.Context()), "err", err)...)
+        http.Error(w, "step must look like 1d or 6h, up to 36525d", http.StatusBadRequest)
+        return
+    }
+    end = end.Add(24*time.Hour - time.Nanosecond) // inclusive
+    if end.Before(start) {
+        http.Error(w, "end is before start", http.StatusBadRequest)
+        return
+    }
+    if step-time.Nanosecond > end.Sub(start) {
+        http.Error(w, "step is longer than the range", http.StatusBadRequest)
+        return
+    }
+    // time.Duration saturates at 292 years, so count rows in days
+    if n := (julianDay(end)-julianDay(start))/step.Hours()*24 + 1; n > ephemerisMaxRows {
+        http.Error(w, fmt.Sprintf("table would have %.0f rows, the limit is %d", n, ephemerisMaxRows), http.StatusBadRequest)
+        return
+    }
+
+    // Check both ends first so an out-of-range table fails before streaming
+    ephStart := time.Now()
+    _, err = ephemerisRows([]time.Time{start, end}, body, opts)
+    observeEphemeris(r.Context(), "ephemeris_table", ephStart)
+    if err != nil {
+        slog.WarnContext(r.Context(), "ephemeris table range rejected", append(requestLogAttrs(r.Context()), "err", err)...)
+        http.Error(w, "dates outside the ephemeris range", http.StatusBadRequest)
+        return
+    }
+
+    format := q.Get("format")
+    filename := fmt.Sprintf("%s-ephemeris-%s-%s", name, start.Format("20060102"), end.Format("20060102"))
+    var out ephemerisWriter
+    switch format {
+    case "", "json":
+        w.Header().Set("Content-Type", "application/json")
+        meta := map[string]string{"body": name, "zodiac": opts.Zodiac, "step": stepText}
+        if opts.sidereal() {
+            meta["ayanamsa"] = opts.Ayanamsa
+        }
+        out = &jsonEphemeris{w: w, meta: meta}
+    case "csv":
+        w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+        w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.csv"`)
+        out = &csvEphemeris{w: csv.NewWriter(w)}
+    case "text":
+        w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+        title := fmt.Sprintf("%s (%s)", strings.ToUpper(name), opts.Zodiac)
+        if opts.sidereal() {
+            title = fmt.Sprintf("%s (sidereal, %s)", strings.ToUpper(name), opts.Ayanamsa)
+        }
+        out = &textEphemeris{w: w, title: title, daily: step%(24*time.Hour) == 0}
+    default:
+        http.Error(w, "format must be json, csv or text", http.StatusBadRequest)
+        return
+    }
+
+    // The headers are sent now; errors from here on can only cut the table
+    // short, and say so at its end so the body is never a silent truncation.
+    rc := http.NewResponseController(w)
+    rc.SetWriteDeadline(time.Now().Add(ephemerisChunkTime))
+    if err := out.header(); err != nil {
+        return
+    }
+    times := make([]time.Time, 0, ephemerisChunkRows)
+    for t := start; !t.After(end); {
+        times = times[:0]
+        for ; !t.After(end) && len(times) < ephemerisChunkRows; t = t.Add(step) {
+            times = append(times, t)
+        }
+        ephStart = time.Now()
+        rows, err := ephemerisRows(times, body, opts)
+        observeEphemeris(r.Context(), "ephemeris_table", ephStart)
+        if err != nil {
+            slog.ErrorContext(r.Context(), "ephemeris table cut short", append(requestLogAttrs(r.Context()), "err", err, "at", times[0])...)
+            out.cutShort("ephemeris unavailable from " + times[0].Format(time.RFC3339))
+            return
+        }
+        if err := out.rows(rows); err != nil {
+            return // client went away
+        }
+        rc.Flush()
+        rc.SetWriteDeadline(time.Now().Add(ephemerisChunkTime))
+    }
+    out.footer()
+}
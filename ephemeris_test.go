@@ -0,0 +1,88 @@
+package main
+
+import (
+    "encoding/json"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+// outOfRangeEphemeris is the fake failing every position the way the
+// library does past the end of its files.
+type outOfRangeEphemeris struct{ fakeEphemeris }
+
+func (*outOfRangeEphemeris) CalcUt(jd float64, body, flags int, xx []float64) error {
+    return errors.New("jd 2816787.5 beyond end of file /srv/ephe/seas_18.se1")
+}
+
+func TestEphemerisTableHidesLibraryErrors(t *testing.T) {
+    for name, c := range map[string]struct {
+        eph   Ephemeris
+        query string
+        want  string
+    }{
+        "range":     {&outOfRangeEphemeris{}, "start=3000-01-01&end=3000-01-02", "dates outside the ephemeris range"},
+        "step":      {&fakeEphemeris{}, "start=2000-01-01&end=2000-01-02&step=1w", "step must look like 1d or 6h, up to 36525d"},
+        "huge step": {&fakeEphemeris{}, "start=2000-01-01&end=2000-01-10&step=1000000000d", "step must look like 1d or 6h, up to 36525d"},
+        "zero step": {&fakeEphemeris{}, "start=2000-01-01&end=2000-01-10&step=0d", "step must look like 1d or 6h, up to 36525d"},
+        "long step": {&fakeEphemeris{}, "start=2000-01-01&end=2000-01-10&step=11d", "step is longer than the range"},
+        "ayanamsa":  {&lahiriOnly{}, "start=2000-01-01&end=2000-01-02&zodiac=sidereal&ayanamsa=fagan_bradley", "zodiac or ayanamsa not available"},
+    } {
+        useEphemeris(t, c.eph)
+        w := httptest.NewRecorder()
+        ephemerisTableHandler(w, httptest.NewRequest(http.MethodGet, "/api/ephemeris?"+c.query, nil))
+        if got := strings.TrimSpace(w.Body.String()); w.Code != http.StatusBadRequest || got != c.want {
+            t.Errorf("%s: status %d %q, want 400 %q", name, w.Code, got, c.want)
+        }
+    }
+}
+
+// gappedEphemeris is the fake with March 2002 missing, inside the second chunk
+// of a daily table from 2000.
+type gappedEphemeris struct{ fakeEphemeris }
+
+func (g *gappedEphemeris) CalcUt(jd float64, body, flags int, xx []float64) error {
+    if jd > julianDay(time.Date(2002, 3, 1, 0, 0, 0, 0, time.UTC)) && jd < julianDay(time.Date(2002, 4, 1, 0, 0, 0, 0, time.UTC)) {
+        return errors.New("gap")
+    }
+    return g.fakeEphemeris.CalcUt(jd, body, flags, xx)
+}
+
+func TestEphemerisTableStepFitsRange(t *testing.T) {
+    useFake(t)
+    // A one-day table takes a one-day step
+    w := httptest.NewRecorder()
+    ephemerisTableHandler(w, httptest.NewRequest(http.MethodGet, "/api/ephemeris?start=2000-01-01&end=2000-01-01&step=1d", nil))
+    var table struct{ Rows []EphemerisRow }
+    if err := json.Unmarshal(w.Body.Bytes(), &table); w.Code != http.StatusOK || err != nil || len(table.Rows) != 1 {
+        t.Errorf("status %d, %d rows, err %v", w.Code, len(table.Rows), err)
+    }
+}
+
+func TestEphemerisTableCutShortSaysSo(t *testing.T) {
+    useEphemeris(t, &gappedEphemeris{})
+    for _, format := range []string{"json", "csv", "text"} {
+        w := httptest.NewRecorder()
+        ephemerisTableHandler(w, httptest.NewRequest(http.MethodGet, "/api/ephemeris?start=2000-01-01&end=2002-12-31&format="+format, nil))
+        body := w.Body.String()
+        if !strings.Contains(body, "ephemeris unavailable from 2001-05-15") {
+            t.Errorf("%s: no error trailer at the end of %q", format, body[max(0, len(body)-200):])
+        }
+        if format != "json" {
+            continue
+        }
+        var table struct {
+            Rows  []EphemerisRow
+            Error string
+        }
+        if err := json.Unmarshal(w.Body.Bytes(), &table); err != nil {
+            t.Fatalf("truncated table isn't valid JSON: %v", err)
+        }
+        if len(table.Rows) != ephemerisChunkRows || table.Error == "" {
+            t.Errorf("%d rows, error %q", len(table.Rows), table.Error)
+        }
+    }
+}
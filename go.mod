@@ -2,4 +2,7 @@ module chiron-oracle
 
 go 1.22
 
-require github.com/mshafiee/swephgo v1.1.0
+require (
+    github.com/mshafiee/swephgo v1.1.0
+    gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,141 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "math"
+    "net/http"
+    "time"
+)
+
+// Build metadata, injected with
+//   go build -ldflags "-X main.version=1.2.3 -X main.commit=$(git rev-parse --short HEAD)"
+var (
+    version = "dev"
+    commit  = "unknown"
+)
+
+// ===== Liveness / readiness =====
+
+// Chiron at J2000.0 (2000-01-01 12:00 TT ≈ UT) from the bundled seas_18.se1.
+const (
+    readinessRefJD        = 2451545.0
+    readinessRefChironLon = 251.6176
+    readinessTolerance    = 0.01 // degrees
+)
+
+type readinessCheck struct {
+    Name  string
+    Check func(ctx context.Context) error
+}
+
+// readinessChecks must all pass before the pod receives traffic. There is no
+// storage check: the service keeps no database or other storage.
+var readinessChecks = []readinessCheck{
+    {"shutdown", checkNotShuttingDown},
+    {"ephemeris", checkEphemeris},
+    {"houses", checkHouses},
+    {"interpretations", checkInterpretations},
+}
+
+func checkEphemeris(ctx context.Context) error {
+    // The full position always goes to Swiss Ephemeris, never the table
+    pos, err := liveChironPosition(readinessRefJD, tropical)
+    if err != nil {
+        return err
+    }
+    lon := pos.Lon
+    if diff := math.Abs(lon - readinessRefChironLon); diff > readinessTolerance {
+        return fmt.Errorf("reference Chiron longitude %.4f differs from expected %.4f", lon, readinessRefChironLon)
+    }
+    return nil
+}
+
+func checkHouses(ctx context.Context) error {
+    // London at J2000; any successful house calculation gives a non-zero Ascendant here
+    if asc := computeAscendant(readinessRefJD, 51.5, -0.12, tropical); asc == 0 {
+        return errors.New("house calculation failed")
+    }
+    return nil
+}
+
+func checkInterpretations(ctx context.Context) error {
+    signs, pairs := interpretationCorpusSize()
+    if signs != 12 || pairs == 0 {
+        return fmt.Errorf("interpretation corpus incomplete: %d signs, %d sign/house pairs", signs, pairs)
+    }
+    return nil
+}
+
+type checkResult struct {
+    Status   string  `json:"status"`
+    Error    string  `json:"error,omitempty"`
+    Duration float64 `json:"duration_ms"`
+}
+
+func runReadinessChecks(ctx context.Context) (bool, map[string]checkResult) {
+    ok := true
+    results := make(map[string]checkResult, len(readinessChecks))
+    for _, c := range readinessChecks {
+        start := time.Now()
+        err := c.Check(ctx)
+        res := checkResult{Status: "ok", Duration: float64(time.Since(start).Microseconds()) / 1000}
+        if err != nil {
+            ok = false
+            res.Status = "failed"
+            res.Error = err.Error()
+        }
+        results[c.Name] = res
+    }
+    return ok, results
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(status)
+    json.NewEncoder(w).Encode(v)
+}
+
+// livezHandler only says the process is up and serving HTTP.
+func livezHandler(w http.ResponseWriter, r *http.Request) {
+    writeJSON(w, http.StatusOK, map[string]interface{}{
+        "status": "ok",
+        "time":   time.Now().Unix(),
+    })
+}
+
+// readyzHandler answers 503 unless a real reading can be produced.
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
+    ok, results := runReadinessChecks(r.Context())
+    status, code := "ready", http.StatusOK
+    if !ok {
+        status, code = "not ready", http.StatusServiceUnavailable
+    }
+    writeJSON(w, code, map[string]interface{}{
+        "status":    status,
+        "checks":    results,
+        "ephemeris": eph.Name(),
+        "version":   version,
+        "commit":    commit,
+    })
+}
+
+// healthHandler keeps the original /api/health contract, now backed by the
+// readiness checks instead of a hardcoded answer.
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+    ok, _ := runReadinessChecks(r.Context())
+    status, code := "healthy", http.StatusOK
+    if !ok {
+        status, code = "unhealthy", http.StatusServiceUnavailable
+    }
+    writeJSON(w, code, map[string]interface{}{
+        "status":    status,
+        "service":   "chiron-oracle",
+        "ephemeris": eph.Name(),
+        "version":   version,
+        "commit":    commit,
+        "time":      time.Now().Unix(),
+    })
+}
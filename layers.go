@@ -0,0 +1,192 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "math"
+    "os"
+    "strconv"
+    "strings"
+)
+
+// ===== Degree-level interpretation layers =====
+
+// Optional refinements below the sign × house reading. Which ones are
+// returned is set by interpretations.layers.
+var interpretationLayers = map[string]bool{"decan": true, "terms": true, "critical": true, "sabian": true}
+
+var decanRulerSystems = map[string]bool{"triplicity": true, "chaldean": true}
+
+var signNames = []string{"Aries", "Taurus", "Gemini", "Cancer", "Leo", "Virgo",
+    "Libra", "Scorpio", "Sagittarius", "Capricorn", "Aquarius", "Pisces"}
+
+// Modern sign rulers, used for triplicity decans.
+var signRulers = []string{"Mars", "Venus", "Mercury", "Moon", "Sun", "Mercury",
+    "Venus", "Pluto", "Jupiter", "Saturn", "Uranus", "Neptune"}
+
+// chaldeanOrder is the planetary order of the faces, starting at 0° Aries.
+var chaldeanOrder = []string{"Mars", "Sun", "Venus", "Mercury", "Moon", "Saturn", "Jupiter"}
+
+type term struct {
+    end   int // exclusive upper degree
+    ruler string
+}
+
+// egyptianTerms lists the Egyptian bounds per sign, as given by Ptolemy.
+var egyptianTerms = [12][5]term{
+    {{6, "Jupiter"}, {12, "Venus"}, {20, "Mercury"}, {25, "Mars"}, {30, "Saturn"}},
+    {{8, "Venus"}, {14, "Mercury"}, {22, "Jupiter"}, {27, "Saturn"}, {30, "Mars"}},
+    {{6, "Mercury"}, {12, "Jupiter"}, {17, "Venus"}, {24, "Mars"}, {30, "Saturn"}},
+    {{7, "Mars"}, {13, "Venus"}, {19, "Mercury"}, {26, "Jupiter"}, {30, "Saturn"}},
+    {{6, "Jupiter"}, {11, "Venus"}, {18, "Saturn"}, {24, "Mercury"}, {30, "Mars"}},
+    {{7, "Mercury"}, {17, "Venus"}, {21, "Jupiter"}, {28, "Mars"}, {30, "Saturn"}},
+    {{6, "Saturn"}, {14, "Mercury"}, {21, "Jupiter"}, {28, "Venus"}, {30, "Mars"}},
+    {{7, "Mars"}, {11, "Venus"}, {19, "Mercury"}, {24, "Jupiter"}, {30, "Saturn"}},
+    {{12, "Jupiter"}, {17, "Venus"}, {21, "Mercury"}, {26, "Saturn"}, {30, "Mars"}},
+    {{7, "Mercury"}, {14, "Jupiter"}, {22, "Venus"}, {26, "Saturn"}, {30, "Mars"}},
+    {{7, "Mercury"}, {13, "Venus"}, {20, "Jupiter"}, {25, "Mars"}, {30, "Saturn"}},
+    {{12, "Venus"}, {16, "Jupiter"}, {19, "Mercury"}, {28, "Mars"}, {30, "Saturn"}},
+}
+
+// decanTexts colour the wound by the decan ruler.
+var decanTexts = map[string]string{
+    "Sun":     "The wound touches the core sense of self and vitality; healing comes through letting yourself be seen as you are.",
+    "Moon":    "The wound is felt in moods, memory and the need for safety; healing comes through learning to mother yourself.",
+    "Mercury": "The wound lives in thought and speech; healing comes through naming what hurts and telling your own story.",
+    "Venus":   "The wound shows in love, pleasure and self-worth; healing comes through receiving as freely as you give.",
+    "Mars":    "The wound flares around anger and desire; healing comes through action that is chosen rather than reactive.",
+    "Jupiter": "The wound concerns faith and meaning; healing comes through a philosophy earned from your own experience.",
+    "Saturn":  "The wound is bound up with duty, limits and time; healing comes slowly, through patient structure.",
+    "Uranus":  "The wound is a sense of not fitting in; healing comes through owning your difference.",
+    "Neptune": "The wound blurs boundaries and ideals; healing comes through compassion that includes yourself.",
+    "Pluto":   "The wound runs deep and tends to surface in crises; healing comes through transformation you stop resisting.",
+}
+
+// termTexts describe how the term ruler conditions the expression of the degree.
+var termTexts = map[string]string{
+    "Mercury": "Mercury's term sharpens the mind's part in the wound: you learn through study, conversation and analysis of what happened.",
+    "Venus":   "Venus's term softens the degree; relationships and beauty become the medicine as much as the trigger.",
+    "Mars":    "Mars's term adds heat; the wound is confronted directly, and courage must be tempered with care.",
+    "Jupiter": "Jupiter's term is generous; teachers, travel and wider perspectives help the wound find meaning.",
+    "Saturn":  "Saturn's term is strict; the lesson takes time and discipline, but what you build from it lasts.",
+}
+
+var criticalTexts = map[string]string{
+    "initial":  "At 0° Chiron sits at the raw beginning of its sign: the wound is new territory, experienced with little precedent and a great deal of intensity.",
+    "anaretic": "At 29°, the anaretic degree, Chiron carries the urgency of completion: the wound feels fated and familiar, and its lesson presses to be finished.",
+}
+
+type DecanLayer struct {
+    Number  int    `json:"number"` // 1-3
+    Ruler   string `json:"ruler"`
+    Rulers  string `json:"rulers"` // triplicity or chaldean
+    Meaning string `json:"text"`
+}
+
+type TermLayer struct {
+    Ruler   string `json:"ruler"`
+    Meaning string `json:"text"`
+}
+
+type CriticalLayer struct {
+    Type    string `json:"type"` // initial (0°) or anaretic (29°)
+    Meaning string `json:"text"`
+}
+
+type SabianLayer struct {
+    Degree string `json:"degree"` // e.g. "Aries 13": Sabian degrees count from 1
+    Symbol string `json:"symbol"`
+}
+
+type DegreeLayers struct {
+    Decan    *DecanLayer    `json:"decan,omitempty"`
+    Term     *TermLayer     `json:"term,omitempty"`
+    Critical *CriticalLayer `json:"critical_degree,omitempty"`
+    Sabian   *SabianLayer   `json:"sabian,omitempty"`
+}
+
+// sabianSymbols holds texts loaded from interpretations.sabian_path, keyed
+// like SabianLayer.Degree. There is no built-in set, so without a file the
+// sabian layer is never returned.
+var sabianSymbols map[string]string
+
+// loadSabianFile reads a JSON object of {"Aries 1": "...", ...}.
+func loadSabianFile(path string) error {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return err
+    }
+    var symbols map[string]string
+    if err := json.Unmarshal(data, &symbols); err != nil {
+        return err
+    }
+    for key := range symbols {
+        sign, deg, ok := strings.Cut(key, " ")
+        n, err := strconv.Atoi(deg)
+        if !ok || err != nil || n < 1 || n > 30 || indexOfSign(sign) < 0 {
+            return fmt.Errorf("sabian key %q: want \"<Sign> <1-30>\"", key)
+        }
+    }
+    sabianSymbols = symbols
+    return nil
+}
+
+func indexOfSign(sign string) int {
+    for i, s := range signNames {
+        if s == sign {
+            return i
+        }
+    }
+    return -1
+}
+
+// computeDegreeLayers resolves the enabled layers for an ecliptic longitude.
+func computeDegreeLayers(lon float64, cfg interpretationsConfig) *DegreeLayers {
+    lon = math.Mod(lon, 360)
+    sign := int(lon/30) % 12
+    deg := int(math.Mod(lon, 30))
+
+    enabled := map[string]bool{}
+    for _, l := range cfg.Layers {
+        enabled[l] = true
+    }
+    layers := &DegreeLayers{}
+
+    if enabled["decan"] {
+        n := deg / 10
+        var ruler string
+        if cfg.DecanRulers == "chaldean" {
+            ruler = chaldeanOrder[(sign*3+n)%7]
+        } else {
+            ruler = signRulers[(sign+4*n)%12]
+        }
+        layers.Decan = &DecanLayer{Number: n + 1, Ruler: ruler, Rulers: cfg.DecanRulers, Meaning: decanTexts[ruler]}
+    }
+    if enabled["terms"] {
+        for _, t := range egyptianTerms[sign] {
+            if deg < t.end {
+                layers.Term = &TermLayer{Ruler: t.ruler, Meaning: termTexts[t.ruler]}
+                break
+            }
+        }
+    }
+    if enabled["critical"] {
+        switch deg {
+        case 0:
+            layers.Critical = &CriticalLayer{Type: "initial", Meaning: criticalTexts["initial"]}
+        case 29:
+            layers.Critical = &CriticalLayer{Type: "anaretic", Meaning: criticalTexts["anaretic"]}
+        }
+    }
+    if enabled["sabian"] {
+        key := fmt.Sprintf("%s %d", signNames[sign], deg+1)
+        if symbol := sabianSymbols[key]; symbol != "" {
+            layers.Sabian = &SabianLayer{Degree: key, Symbol: symbol}
+        }
+    }
+
+    if *layers == (DegreeLayers{}) {
+        return nil
+    }
+    return layers
+}
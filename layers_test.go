@@ -0,0 +1,44 @@
+package main
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestSabianLayerNeedsTexts(t *testing.T) {
+    cfg := defaultConfig().Interpretations
+    cfg.Layers = append(cfg.Layers, "sabian")
+    lon := 12.5 // Aries 13
+
+    saved := sabianSymbols
+    t.Cleanup(func() { sabianSymbols = saved })
+    sabianSymbols = nil
+    if l := computeDegreeLayers(lon, cfg); l == nil || l.Sabian != nil {
+        t.Errorf("sabian layer without texts: %+v", l)
+    }
+
+    sabianSymbols = map[string]string{"Aries 13": "symbol text"}
+    l := computeDegreeLayers(lon, cfg)
+    if l == nil || l.Sabian == nil || l.Sabian.Degree != "Aries 13" || l.Sabian.Symbol == "" {
+        t.Errorf("sabian layer with a text: %+v", l)
+    }
+    if l := computeDegreeLayers(lon+1, cfg); l.Sabian != nil {
+        t.Errorf("Aries 14 has no text but got %+v", l.Sabian)
+    }
+
+    c := defaultConfig()
+    c.Interpretations.Layers = cfg.Layers
+    if err := c.validate(); err == nil || !strings.Contains(err.Error(), "sabian_path") {
+        t.Errorf("sabian layer without sabian_path: %v", err)
+    }
+}
+
+func TestDefaultLayersHaveText(t *testing.T) {
+    cfg := defaultConfig().Interpretations
+    for _, lon := range []float64{0, 29.5, 95, 181, 359.9} {
+        l := computeDegreeLayers(lon, cfg)
+        if l == nil || l.Decan == nil || l.Decan.Meaning == "" || l.Term == nil || l.Term.Meaning == "" {
+            t.Errorf("%.1f°: %+v", lon, l)
+        }
+    }
+}
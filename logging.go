@@ -0,0 +1,245 @@
+package main
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "log/slog"
+    "net/http"
+    "os"
+    "strings"
+    "sync"
+    "time"
+)
+
+// ===== Structured logging =====
+
+// Log attribute keys that carry birth data. They are redacted unless
+// LOG_BIRTH_DATA=true, since a birth moment plus place identifies a person.
+var birthDataKeys = map[string]bool{
+    "birth_utc": true,
+    "birth_jd":  true,
+    "lat":       true,
+    "lon":       true,
+}
+
+func parseLogLevel(s string) slog.Level {
+    switch strings.ToLower(s) {
+    case "debug":
+        return slog.LevelDebug
+    case "warn", "warning":
+        return slog.LevelWarn
+    case "error":
+        return slog.LevelError
+    default:
+        return slog.LevelInfo
+    }
+}
+
+// newLogger builds the JSON logger used by the whole service.
+func newLogger(level slog.Level, logBirthData bool) *slog.Logger {
+    opts := &slog.HandlerOptions{
+        Level: level,
+        ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+            if !logBirthData && birthDataKeys[a.Key] {
+                return slog.String(a.Key, "[REDACTED]")
+            }
+            return a
+        },
+    }
+    return slog.New(slog.NewJSONHandler(os.Stdout, opts))
+}
+
+func setupLogging(cfg logConfig) {
+    slog.SetDefault(newLogger(parseLogLevel(cfg.Level), cfg.BirthData))
+}
+
+// ===== Request tracing =====
+
+type traceContext struct {
+    TraceID  string
+    ParentID string // span ID of the caller, empty when we started the trace
+    SpanID   string
+    Flags    string
+}
+
+// traceparent formats the header we hand to downstream services and return
+// to the caller: our span becomes their parent.
+func (t traceContext) traceparent() string {
+    return "00-" + t.TraceID + "-" + t.SpanID + "-" + t.Flags
+}
+
+func randomHex(n int) string {
+    b := make([]byte, n)
+    if _, err := rand.Read(b); err != nil {
+        // crypto/rand never fails on supported platforms; keep IDs non-empty regardless
+        return strings.Repeat("0", 2*n-1) + "1"
+    }
+    return hex.EncodeToString(b)
+}
+
+// isLowerHex reports whether s is n lowercase hex digits.
+func isLowerHex(s string, n int) bool {
+    if len(s) != n {
+        return false
+    }
+    for _, c := range s {
+        if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+            return false
+        }
+    }
+    return true
+}
+
+// isTraceID is isLowerHex for trace and span IDs, which may not be all zeros.
+func isTraceID(s string, n int) bool {
+    return isLowerHex(s, n) && strings.Trim(s, "0") != ""
+}
+
+// parseTraceparent accepts a W3C traceparent header, or starts a new trace
+// when it is missing or malformed.
+func parseTraceparent(h string) traceContext {
+    tc := traceContext{SpanID: randomHex(8), Flags: "01"}
+    parts := strings.Split(strings.TrimSpace(h), "-")
+    // Version 00 has exactly four fields; later versions may append more
+    if (len(parts) == 4 || len(parts) > 4 && parts[0] != "00") && isLowerHex(parts[0], 2) && parts[0] != "ff" &&
+        isTraceID(parts[1], 32) && isTraceID(parts[2], 16) && isLowerHex(parts[3], 2) {
+        tc.TraceID = parts[1]
+        tc.ParentID = parts[2]
+        tc.Flags = parts[3]
+        return tc
+    }
+    tc.TraceID = randomHex(16)
+    return tc
+}
+
+// requestInfo travels in the request context so handlers can report
+// details (like ephemeris timing) that end up in the access log line.
+type requestInfo struct {
+    ID    string
+    Trace traceContext
+
+    mu        sync.Mutex
+    ephemeris time.Duration
+}
+
+type requestInfoKey struct{}
+
+func requestInfoFrom(ctx context.Context) *requestInfo {
+    if ri, ok := ctx.Value(requestInfoKey{}).(*requestInfo); ok {
+        return ri
+    }
+    return nil
+}
+
+// addEphemerisTime records time spent inside Swiss Ephemeris for this request.
+func addEphemerisTime(ctx context.Context, d time.Duration) {
+    if ri := requestInfoFrom(ctx); ri != nil {
+        ri.mu.Lock()
+        ri.ephemeris += d
+        ri.mu.Unlock()
+    }
+}
+
+// requestLogAttrs returns the correlation fields for log lines emitted while
+// handling a request.
+func requestLogAttrs(ctx context.Context) []any {
+    ri := requestInfoFrom(ctx)
+    if ri == nil {
+        return nil
+    }
+    return []any{"request_id", ri.ID, "trace_id", ri.Trace.TraceID, "span_id", ri.Trace.SpanID}
+}
+
+type statusRecorder struct {
+    http.ResponseWriter
+    status int
+    bytes  int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+    if s.status == 0 {
+        s.status = code
+    }
+    s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+    if s.status == 0 {
+        s.status = http.StatusOK
+    }
+    n, err := s.ResponseWriter.Write(b)
+    s.bytes += n
+    return n, err
+}
+
+func (s *statusRecorder) Flush() {
+    if f, ok := s.ResponseWriter.(http.Flusher); ok {
+        f.Flush()
+    }
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+    return s.ResponseWriter
+}
+
+// routePattern returns the mux pattern that will serve r, used instead of the
+// raw path in logs and metric labels.
+func routePattern(mux *http.ServeMux, r *http.Request) string {
+    if _, pattern := mux.Handler(r); pattern != "" {
+        return pattern
+    }
+    return "unmatched"
+}
+
+// requestLogger assigns a request ID, continues or starts a W3C trace and
+// writes one structured access log line per request.
+func requestLogger(mux *http.ServeMux, next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        start := time.Now()
+
+        ri := &requestInfo{
+            ID:    r.Header.Get("X-Request-ID"),
+            Trace: parseTraceparent(r.Header.Get("traceparent")),
+        }
+        if ri.ID == "" || len(ri.ID) > 128 {
+            ri.ID = randomHex(12)
+        }
+        w.Header().Set("X-Request-ID", ri.ID)
+        w.Header().Set("traceparent", ri.Trace.traceparent())
+
+        route := routePattern(mux, r)
+
+        rec := &statusRecorder{ResponseWriter: w}
+        ctx := context.WithValue(r.Context(), requestInfoKey{}, ri)
+        next.ServeHTTP(rec, r.WithContext(ctx))
+
+        if rec.status == 0 {
+            rec.status = http.StatusOK
+        }
+        level := slog.LevelInfo
+        if rec.status >= 500 {
+            level = slog.LevelError
+        }
+
+        ri.mu.Lock()
+        ephemeris := ri.ephemeris
+        ri.mu.Unlock()
+
+        attrs := append(requestLogAttrs(ctx),
+            "method", r.Method,
+            "route", route,
+            "status", rec.status,
+            "bytes", rec.bytes,
+            "latency_ms", float64(time.Since(start).Microseconds())/1000,
+            "ephemeris_ms", float64(ephemeris.Microseconds())/1000,
+        )
+        if ri.Trace.ParentID != "" {
+            attrs = append(attrs, "parent_span_id", ri.Trace.ParentID)
+        }
+        if cn := clientCertSubject(r); cn != "" {
+            attrs = append(attrs, "client_cn", cn)
+        }
+        slog.Log(ctx, level, "request", attrs...)
+    })
+}
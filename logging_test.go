@@ -0,0 +1,58 @@
+package main
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+    const (
+        trace  = "4bf92f3577b34da6a3ce929d0e0e4736"
+        parent = "00f067aa0ba902b7"
+    )
+    for name, c := range map[string]struct {
+        header string
+        ok     bool
+    }{
+        "valid":           {"00-" + trace + "-" + parent + "-01", true},
+        "not sampled":     {"00-" + trace + "-" + parent + "-00", true},
+        "later version":   {"01-" + trace + "-" + parent + "-01-extra", true},
+        "extra on 00":     {"00-" + trace + "-" + parent + "-01-extra", false},
+        "hex version":     {"zz-" + trace + "-" + parent + "-01", false},
+        "version ff":      {"ff-" + trace + "-" + parent + "-01", false},
+        "upper version":   {"0A-" + trace + "-" + parent + "-01", false},
+        "hex flags":       {"00-" + trace + "-" + parent + "-zz", false},
+        "upper flags":     {"00-" + trace + "-" + parent + "-0F", false},
+        "zero trace":      {"00-" + strings.Repeat("0", 32) + "-" + parent + "-01", false},
+        "zero parent":     {"00-" + trace + "-" + strings.Repeat("0", 16) + "-01", false},
+        "short trace":     {"00-" + trace[1:] + "-" + parent + "-01", false},
+        "missing":         {"", false},
+        "too few fields":  {"00-" + trace + "-" + parent, false},
+        "upper trace id":  {"00-" + strings.ToUpper(trace) + "-" + parent + "-01", false},
+        "three-char flag": {"00-" + trace + "-" + parent + "-001", false},
+    } {
+        tc := parseTraceparent(c.header)
+        if got := tc.TraceID == trace && tc.ParentID == parent; got != c.ok {
+            t.Errorf("%s: continued trace %v, want %v (%+v)", name, got, c.ok, tc)
+        }
+        if !isTraceID(tc.TraceID, 32) || !isTraceID(tc.SpanID, 16) || !isLowerHex(tc.Flags, 2) {
+            t.Errorf("%s: invalid context %+v", name, tc)
+        }
+    }
+}
+
+func TestTraceparentEchoIsValid(t *testing.T) {
+    mux := http.NewServeMux()
+    mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {})
+    r := httptest.NewRequest(http.MethodGet, "/", nil)
+    r.Header.Set("traceparent", "zz-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz")
+    w := httptest.NewRecorder()
+    requestLogger(mux, mux).ServeHTTP(w, r)
+    got := w.Header().Get("traceparent")
+    if parts := strings.Split(got, "-"); len(parts) != 4 || parts[0] != "00" || strings.Contains(got, "zz") ||
+        !isTraceID(parts[1], 32) || !isTraceID(parts[2], 16) || !isLowerHex(parts[3], 2) {
+        t.Errorf("traceparent %q echoed back", got)
+    }
+}
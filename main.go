@@ -1,21 +1,45 @@
 package main
 
 import (
+    "bytes"
     "encoding/json"
+    "errors"
     "fmt"
-    "log"
+    "log/slog"
     "math"
     "net/http"
     "os"
+    "strconv"
+    "sync"
     "time"
-
-    swe "github.com/mshafiee/swephgo"
 )
 
 // ===== Swiss Ephemeris constants (manual defs) =====
 const (
-    SE_CHIRON    = 15 // Chiron’s planet number
-    SEFLG_SWIEPH = 2  // Use Swiss Ephemeris computations
+    SE_CHIRON      = 15        // Chiron’s planet number
+    SEFLG_SWIEPH   = 2         // Use Swiss Ephemeris computations
+    SEFLG_SIDEREAL = 64 * 1024 // Sidereal positions, ayanamsa set via swe.SetSidMode
+
+    SE_ECL_NUT       = -1        // Pseudo-body returning obliquity and nutation
+    SEFLG_HELCTR     = 8         // Heliocentric position
+    SEFLG_SPEED      = 256       // Also compute daily speed (xx[3..5])
+    SEFLG_EQUATORIAL = 2 * 1024  // Right ascension / declination instead of ecliptic
+    SEFLG_TOPOCTR    = 32 * 1024 // Topocentric position, observer set via swe.SetTopo
+
+    SEFLG_JPLEPH = 1 // Use a JPL DE file, set via swe.SetJplFile
+    SEFLG_MOSEPH = 4 // Use the built-in Moshier theory, no files needed
+    SE_GREG_CAL  = 1 // Gregorian calendar for swe.Julday
+
+    SE_SUN     = 0
+    SE_MOON    = 1
+    SE_MERCURY = 2
+    SE_VENUS   = 3
+    SE_MARS    = 4
+    SE_JUPITER = 5
+    SE_SATURN  = 6
+    SE_URANUS  = 7
+    SE_NEPTUNE = 8
+    SE_PLUTO   = 9
 )
 
 
@@ -29,64 +53,137 @@ type BirthData struct {
     Lat      float64 `json:"lat"`
     Lon      float64 `json:"lon"`
     Timezone string  `json:"timezone"`
+
+    // Optional calculation settings; empty uses the configured defaults
+    Zodiac       string  `json:"zodiac,omitempty"`
+    Ayanamsa     string  `json:"ayanamsa,omitempty"`
+    Topocentric  bool    `json:"topocentric,omitempty"`  // observer at Lat/Lon/Altitude instead of Earth's centre
+    Heliocentric bool    `json:"heliocentric,omitempty"` // seen from the Sun
+    Equatorial   bool    `json:"equatorial,omitempty"`   // also return right ascension / declination
+    Altitude     float64 `json:"altitude,omitempty"`     // metres above sea level, for topocentric
+    UnknownTime  bool    `json:"unknown_time,omitempty"` // Hour is ignored; the whole birth day is computed
+
+    // Where the client lives now, for a relocation reading alongside the natal one
+    RelocationLat *float64 `json:"relocation_lat,omitempty"`
+    RelocationLon *float64 `json:"relocation_lon,omitempty"`
 }
 
 
 type ChironReading struct {
-    Sign             string  `json:"sign"`
-    Degree           float64 `json:"degree"`
-    House            int     `json:"house"`
-    TraditionalWound string  `json:"traditional_wound"`
-    LHPStrength      string  `json:"lhp_strength"`
-    Timestamp        int64   `json:"timestamp"`
+    Sign             string            `json:"sign"`
+    Degree           float64           `json:"degree"`
+    House            int               `json:"house"`
+    TraditionalWound string            `json:"traditional_wound"`
+    LHPStrength      string            `json:"lhp_strength"`
+    Timestamp        int64             `json:"timestamp"`
+    Zodiac           string            `json:"zodiac"`
+    Ayanamsa         string            `json:"ayanamsa,omitempty"`
+    AyanamsaValue    float64           `json:"ayanamsa_value,omitempty"` // degrees subtracted from the tropical position
+    Center           string            `json:"center"`                   // geocentric, topocentric or heliocentric
+    Latitude         float64           `json:"latitude"`                 // ecliptic latitude
+    Distance         float64           `json:"distance_au"`
+    Declination      float64           `json:"declination"`
+    OutOfBounds      bool              `json:"out_of_bounds"` // declination beyond the Sun's maximum (the obliquity)
+    Equatorial       *EquatorialCoords `json:"equatorial,omitempty"`
+
+    // Motion
+    Speed           float64         `json:"speed"` // degrees per day, negative when retrograde
+    Retrograde      bool            `json:"retrograde"`
+    Stationary      bool            `json:"stationary"` // within ephemeris.station_window_days of a station
+    PreviousStation *Station        `json:"previous_station,omitempty"`
+    NextStation     *Station        `json:"next_station,omitempty"`
+    RetrogradeText  *Interpretation `json:"retrograde_interpretation,omitempty"`
+
+    Layers *DegreeLayers `json:"layers,omitempty"` // decan, term, critical degree and Sabian symbol
+
+    // Proximity to the angles and to the next house cusp
+    Angles        []AngleContact `json:"angles,omitempty"`
+    CuspInfluence *CuspInfluence `json:"cusp_influence,omitempty"`
+
+    Relocated *RelocatedReading `json:"relocated,omitempty"`
 }
 
-func healthHandler(w http.ResponseWriter, r *http.Request) {
-    w.Header().Set("Content-Type", "application/json")
-    json.NewEncoder(w).Encode(map[string]interface{}{
-        "status":  "healthy",
-        "service": "chiron-oracle",
-        "version": "1.0.0",
-        "time":    time.Now().Unix(),
-    })
+type EquatorialCoords struct {
+    RightAscension float64 `json:"right_ascension"` // degrees
+    Declination    float64 `json:"declination"`
 }
 
 func chironHandler(w http.ResponseWriter, r *http.Request) {
     var req BirthData
-    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        http.Error(w, err.Error(), http.StatusBadRequest)
+    if err := decodeJSON(w, r, &req); err != nil {
+        writeDecodeError(w, err)
         return
     }
 
-    // Convert fractional hour into hour + minute
-    hour := int(req.Hour)
-    minute := int((req.Hour - float64(hour)) * 60)
+    utc, loc, err := birthTime(req)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
 
-    // Build local time from request
-    loc, err := time.LoadLocation(req.Timezone)
+    opts, err := resolveCalcOptions(req)
     if err != nil {
-        http.Error(w, "invalid timezone", http.StatusBadRequest)
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    if err := validateRelocation(req); err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    if req.UnknownTime {
+        unknownTimeHandler(w, r, req, loc, opts)
         return
     }
-    local := time.Date(req.Year, time.Month(req.Month), req.Day, hour, minute, 0, 0, loc)
-    utc := local.UTC()
 
     // Julian Day
     jd := julianDay(utc)
 
-    // Compute Chiron longitude
-    chironLon := computeChironLongitude(jd)
+    // Compute Chiron longitude in the chosen zodiac
+    ephStart := time.Now()
+    pos, err := computeChironPosition(jd, opts)
+    observeEphemeris(r.Context(), "chiron", ephStart)
+    if err != nil {
+        http.Error(w, "ephemeris unavailable", http.StatusInternalServerError)
+        return
+    }
+    chironLon := pos.Lon
 
     // Derive sign and degree
     sign := signFromLongitude(chironLon)
     degree := math.Mod(chironLon, 30)
 
-    // Compute Ascendant longitude
-    ascLon := computeAscendant(jd, req.Lat, req.Lon)
-    ascSignIndex := int(math.Floor(ascLon / 30.0)) % 12
+    // Angles and house in the configured system
+    natal, err := placeInHouses(r.Context(), jd, req.Lat, req.Lon, chironLon, opts)
+    if err != nil {
+        http.Error(w, "house calculation failed", http.StatusInternalServerError)
+        return
+    }
+    house := natal.House
+
+    // Same moment, different place: only the angles and houses move
+    var relocated *placement
+    if req.RelocationLat != nil && req.RelocationLon != nil {
+        p, err := placeInHouses(r.Context(), jd, *req.RelocationLat, *req.RelocationLon, chironLon, opts)
+        if err != nil {
+            http.Error(w, "relocated house calculation failed", http.StatusInternalServerError)
+            return
+        }
+        relocated = &p
+    }
+
+    ephStart = time.Now()
+    prevStation, nextStation, err := computeStations(jd, opts)
+    observeEphemeris(r.Context(), "stations", ephStart)
+    if err != nil {
+        http.Error(w, "station search failed", http.StatusInternalServerError)
+        return
+    }
 
-    // House calculation
-    house := wholeSignHouse(ascSignIndex, chironLon)
+    ayanamsa, err := computeAyanamsa(jd, opts)
+    if err != nil {
+        http.Error(w, "ayanamsa calculation failed", http.StatusInternalServerError)
+        return
+    }
 
     // Get interpretation text
     wound, strength := getInterpretation(sign, house)
@@ -99,53 +196,89 @@ func chironHandler(w http.ResponseWriter, r *http.Request) {
         TraditionalWound: wound,
         LHPStrength:      strength,
         Timestamp:        utc.Unix(),
+        Zodiac:           opts.Zodiac,
+        Ayanamsa:         opts.Ayanamsa,
+        AyanamsaValue:    round6(ayanamsa),
+        Center:           opts.center(),
+        Latitude:         round6(pos.Lat),
+        Distance:         round6(pos.Dist),
+        Declination:      round6(pos.Dec),
+        OutOfBounds:      math.Abs(pos.Dec) > pos.Obliquity,
+    }
+    resp.Speed = round6(pos.Speed)
+    resp.Retrograde = pos.Speed < 0
+    resp.Stationary = isStationary(prevStation, nextStation, appConfig.Ephemeris.StationWindowDays)
+    resp.PreviousStation, resp.NextStation = prevStation, nextStation
+    if resp.Retrograde {
+        resp.RetrogradeText = getRetrogradeInterpretation(sign)
+    }
+    resp.Layers = computeDegreeLayers(chironLon, appConfig.Interpretations)
+    resp.Angles = findAngleContacts(chironLon, natal.Asc, natal.MC, appConfig.Ephemeris.AngleOrb)
+    resp.CuspInfluence = findCuspInfluence(chironLon, house, natal.Cusps, sign, appConfig.Ephemeris.CuspOrb)
+    if relocated != nil {
+        resp.Relocated = newRelocatedReading(*req.RelocationLat, *req.RelocationLon, chironLon, sign, house, *relocated)
+    }
+    if req.Equatorial {
+        resp.Equatorial = &EquatorialCoords{RightAscension: round6(pos.RA), Declination: round6(pos.Dec)}
     }
 
+    readingsBySignHouse.inc(sign, strconv.Itoa(house))
+
     // Return JSON
     w.Header().Set("Content-Type", "application/json")
     if err := json.NewEncoder(w).Encode(resp); err != nil {
-        log.Printf("encode error: %v", err)
+        slog.ErrorContext(r.Context(), "encode error", append(requestLogAttrs(r.Context()), "err", err)...)
         http.Error(w, "failed to encode response", http.StatusInternalServerError)
         return
     }
 
-    // Debug log
-    log.Printf("UTC: %s | JD: %.6f | ChironLon: %.6f | Sign: %s | AscLon: %.6f | House: %d",
-        utc.Format(time.RFC3339), jd, chironLon, sign, ascLon, house)
+    // Debug log; birth data is redacted unless LOG_BIRTH_DATA is set
+    slog.DebugContext(r.Context(), "reading computed", append(requestLogAttrs(r.Context()),
+        "birth_utc", utc.Format(time.RFC3339), "birth_jd", jd, "lat", req.Lat, "lon", req.Lon,
+        "chiron_lon", chironLon, "sign", sign, "house", house)...)
 }
-func main() {
-    // Root route serves HTML frontend
-    http.HandleFunc("/", homeHandler)
-
-    // API routes
-    http.HandleFunc("/api/health", healthHandler)
-    http.HandleFunc("/api/chiron", chironHandler)
-
-    port := os.Getenv("PORT")
-    if port == "" {
-        port = "8080"
-    }
 
-    log.Printf("🚀 Chiron Oracle starting on port %s", port)
-    log.Printf("📡 Local: http://localhost:%s", port)
-
-    log.Fatal(http.ListenAndServe(":"+port, nil))
-}
-func computeAscendant(jd, lat, lon float64) float64 {
+func computeAscendant(jd, lat, lon float64, opts calcOptions) float64 {
     cusps := make([]float64, 13) // 1..12 used
     ascmc := make([]float64, 10)
-    // serr is not part of swe.Houses in most Go bindings; remove it if not in the signature
-    // serr := make([]byte, 256)
-
-    // If your binding expects a rune/byte for hsys:
-    if ret := swe.Houses(jd, lat, lon, 'P', cusps, ascmc); ret < 0 {
-        // If your binding provides an error string via another call, log it there.
-        // log.Printf("Swiss Ephemeris error (Houses): %s", string(serr))
+    var err error
+    withEphemeris(opts, func() {
+        err = eph.HousesEx(jd, opts.houseFlags(), lat, lon, 'P', cusps, ascmc)
+    })
+    if err != nil {
+        ephemerisErrors.inc("houses")
         return 0.0
     }
     return ascmc[0] // Ascendant longitude
 }
 
+// computeHouseCusps returns cusps[1..12] for the given house system.
+func computeHouseCusps(jd, lat, lon float64, hsys byte, opts calcOptions) ([]float64, error) {
+    cusps := make([]float64, 13)
+    ascmc := make([]float64, 10)
+    var err error
+    withEphemeris(opts, func() {
+        err = eph.HousesEx(jd, opts.houseFlags(), lat, lon, int(hsys), cusps, ascmc)
+    })
+    if err != nil {
+        ephemerisErrors.inc("houses")
+        return nil, fmt.Errorf("house system %q failed at latitude %.2f", hsys, lat)
+    }
+    return cusps, nil
+}
+
+// houseFromCusps finds the house whose cusp span contains longDeg.
+func houseFromCusps(cusps []float64, longDeg float64) int {
+    for i := 1; i <= 12; i++ {
+        next := cusps[i%12+1]
+        span := math.Mod(next-cusps[i]+360, 360)
+        if math.Mod(longDeg-cusps[i]+360, 360) < span {
+            return i
+        }
+    }
+    return 1
+}
+
 
 // ===== Handlers =====
 
@@ -269,36 +402,110 @@ func homeHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 
+// birthTime converts the request's local date, fractional hour and zone to UTC.
+func birthTime(req BirthData) (time.Time, *time.Location, error) {
+    // Convert fractional hour into hour + minute
+    hour := int(req.Hour)
+    minute := int((req.Hour - float64(hour)) * 60)
+
+    loc, err := time.LoadLocation(req.Timezone)
+    if err != nil {
+        return time.Time{}, nil, errors.New("invalid timezone")
+    }
+    local := time.Date(req.Year, time.Month(req.Month), req.Day, hour, minute, 0, 0, loc)
+    return local.UTC(), loc, nil
+}
+
 func julianDay(t time.Time) float64 {
-    year := t.Year()
-    month := int(t.Month())
-    day := t.Day()
     hour := float64(t.Hour()) + float64(t.Minute())/60.0 + float64(t.Second())/3600.0
+    return eph.JulDay(t.Year(), int(t.Month()), t.Day(), hour)
+}
 
-    if month <= 2 {
-        year -= 1
-        month += 12
+func computeChironLongitude(jd float64, opts calcOptions) (float64, error) {
+    if lon, _, ok := chironFromTable(jd, opts.flags()); ok {
+        return lon, nil
     }
-    A := year / 100
-    B := 2 - A + A/4
+    pos, err := computeChironPosition(jd, opts)
+    return pos.Lon, err
+}
 
-    jd := math.Floor(365.25*float64(year+4716)) +
-        math.Floor(30.6001*float64(month+1)) +
-        float64(day) + float64(B) - 1524.5 +
-        hour/24.0
+// chironPosition keeps the full swe.CalcUt output rather than just xx[0].
+type chironPosition struct {
+    Lon, Lat, Dist float64 // ecliptic, in the chosen zodiac
+    Speed          float64 // longitude, degrees per day
+    RA, Dec        float64 // equatorial, degrees
+    Obliquity      float64 // true obliquity of the ecliptic at jd
+}
 
-    return jd
+// computeChironPosition answers tropical geocentric requests from the
+// embedded table, which leaves only the obliquity to the ephemeris.
+func computeChironPosition(jd float64, opts calcOptions) (chironPosition, error) {
+    if chironTableUsable(opts.flags()) {
+        if lon, lat, dist, speed, ok := chironTable.at(jd); ok {
+            nut := make([]float64, 6)
+            var err error
+            withEphemeris(opts, func() { err = eph.CalcUt(jd, SE_ECL_NUT, 0, nut) })
+            if err == nil {
+                ra, dec := eclipticToEquatorial(lon, lat, nut[0])
+                return chironPosition{
+                    Lon: lon, Lat: lat, Dist: dist, Speed: speed,
+                    RA: ra, Dec: dec,
+                    Obliquity: nut[0],
+                }, nil
+            }
+        }
+    }
+    return liveChironPosition(jd, opts)
 }
 
-func computeChironLongitude(jd float64) float64 {
-    xx := make([]float64, 6)
-    serr := make([]byte, 256)
-    if ret := swe.CalcUt(jd, SE_CHIRON, SEFLG_SWIEPH, xx, serr); ret < 0 {
+// liveChironPosition always asks the ephemeris, never the table.
+func liveChironPosition(jd float64, opts calcOptions) (chironPosition, error) {
+    ecl := make([]float64, 6)
+    equ := make([]float64, 6)
+    nut := make([]float64, 6)
+    var err error
+    withEphemeris(opts, func() {
+        if err = eph.CalcUt(jd, SE_CHIRON, opts.flags(), ecl); err != nil {
+            return
+        }
+        // Equatorial coordinates have no zodiac, so drop the sidereal bit
+        if err = eph.CalcUt(jd, SE_CHIRON, (opts.flags()&^SEFLG_SIDEREAL)|SEFLG_EQUATORIAL, equ); err != nil {
+            return
+        }
+        err = eph.CalcUt(jd, SE_ECL_NUT, 0, nut)
+    })
+    if err != nil {
 
-        log.Printf("Swiss Ephemeris error: %s", string(serr))
-        return 0.0
+        ephemerisErrors.inc("chiron")
+        slog.Error("ephemeris error", "backend", eph.Name(), "err", err)
+        return chironPosition{}, err
+    }
+    return chironPosition{
+        Lon: ecl[0], Lat: ecl[1], Dist: ecl[2], Speed: ecl[3],
+        RA: equ[0], Dec: equ[1],
+        Obliquity: nut[0],
+    }, nil
+}
+
+// eclipticToEquatorial rotates ecliptic longitude and latitude into right
+// ascension and declination for obliquity eps, all in degrees.
+func eclipticToEquatorial(lon, lat, eps float64) (ra, dec float64) {
+    l, b, e := lon*deg2rad, lat*deg2rad, eps*deg2rad
+    ra = math.Atan2(math.Sin(l)*math.Cos(e)-math.Tan(b)*math.Sin(e), math.Cos(l)) / deg2rad
+    dec = math.Asin(math.Sin(b)*math.Cos(e)+math.Cos(b)*math.Sin(e)*math.Sin(l)) / deg2rad
+    return normDeg(ra), dec
+}
+
+func round6(x float64) float64 {
+    return math.Round(x*1e6) / 1e6
+}
+
+// cString trims a NUL-terminated buffer filled in by Swiss Ephemeris.
+func cString(b []byte) string {
+    if i := bytes.IndexByte(b, 0); i >= 0 {
+        b = b[:i]
     }
-    return xx[0]
+    return string(b)
 }
 
 func signFromLongitude(longDeg float64) string {
@@ -322,7 +529,67 @@ func wholeSignHouse(ascSignIndex int, longDeg float64) int {
 
 
 // --- Interpretations ---
+
+var (
+    interpretationsOnce sync.Once
+    interpretationCache map[string]map[int][2]string
+)
+
+// getInterpretation looks up the sign × house texts. The table is built once
+// on first use rather than on every request.
 func getInterpretation(sign string, house int) (string, string) {
+    interpretationsOnce.Do(func() { interpretationCache = loadInterpretations() })
+
+    // Default fallback if sign/house not found
+    if houses, ok := interpretationCache[sign]; ok {
+        if pair, ok := houses[house]; ok {
+            interpretationLookups.inc("hit")
+            return pair[0], pair[1]
+        }
+    }
+    interpretationLookups.inc("miss")
+    return "No interpretation available.", "No strength available."
+}
+
+// interpretationCorpusSize reports how many signs and sign/house pairs are loaded.
+func interpretationCorpusSize() (signs, pairs int) {
+    interpretationsOnce.Do(func() { interpretationCache = loadInterpretations() })
+    for _, houses := range interpretationCache {
+        pairs += len(houses)
+    }
+    return len(interpretationCache), pairs
+}
+
+// loadInterpretationFile replaces the built-in texts with a JSON corpus of
+// the form {"Aries": {"1": {"traditional_wound": "...", "lhp_strength": "..."}}}.
+func loadInterpretationFile(path string) error {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return err
+    }
+    var corpus map[string]map[int]struct {
+        TraditionalWound string `json:"traditional_wound"`
+        LHPStrength      string `json:"lhp_strength"`
+    }
+    if err := json.Unmarshal(data, &corpus); err != nil {
+        return err
+    }
+
+    table := make(map[string]map[int][2]string, len(corpus))
+    for sign, houses := range corpus {
+        table[sign] = make(map[int][2]string, len(houses))
+        for house, text := range houses {
+            if house < 1 || house > 12 {
+                return fmt.Errorf("%s: house %d out of range", sign, house)
+            }
+            table[sign][house] = [2]string{text.TraditionalWound, text.LHPStrength}
+        }
+    }
+    interpretationsOnce.Do(func() { interpretationCache = table })
+    return nil
+}
+
+func loadInterpretations() map[string]map[int][2]string {
     interpretations := map[string]map[int][2]string{
         "Aries": {
             1: {
@@ -927,11 +1194,5 @@ func getInterpretation(sign string, house int) (string, string) {
         },
     } // end of interpretations map
 
-    // Default fallback if sign/house not found
-    if houses, ok := interpretations[sign]; ok {
-        if pair, ok := houses[house]; ok {
-            return pair[0], pair[1]
-        }
-    }
-    return "No interpretation available.", "No strength available."
+    return interpretations
 }
@@ -0,0 +1,171 @@
+package main
+
+import (
+    "encoding/json"
+    "math"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+// postReading runs chironHandler on body and decodes the answer.
+func postReading(t *testing.T, body string) map[string]interface{} {
+    t.Helper()
+    r := httptest.NewRequest(http.MethodPost, "/api/chiron", strings.NewReader(body))
+    r.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    chironHandler(w, r)
+    if w.Code != http.StatusOK {
+        t.Fatalf("status %d: %s", w.Code, w.Body.String())
+    }
+    if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+        t.Errorf("Content-Type %q", ct)
+    }
+    var out map[string]interface{}
+    if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+        t.Fatal(err)
+    }
+    return out
+}
+
+const londonBirth = `{"year": 1990, "month": 6, "day": 15, "hour": 14.5,
+    "lat": 51.5074, "lon": -0.1278, "timezone": "Europe/London"}`
+
+func TestChironHandlerShape(t *testing.T) {
+    useFake(t)
+    out := postReading(t, londonBirth)
+
+    for _, key := range []string{
+        "sign", "degree", "house", "traditional_wound", "lhp_strength", "timestamp",
+        "zodiac", "center", "latitude", "distance_au", "declination", "out_of_bounds",
+        "speed", "retrograde", "stationary", "layers",
+    } {
+        if _, ok := out[key]; !ok {
+            t.Errorf("missing %q", key)
+        }
+    }
+    // Only present when asked for or when there is something to say
+    for _, key := range []string{"ayanamsa", "equatorial", "relocated", "previous_station", "next_station"} {
+        if _, ok := out[key]; ok {
+            t.Errorf("unexpected %q", key)
+        }
+    }
+
+    birth := time.Date(1990, 6, 15, 13, 30, 0, 0, time.UTC) // BST
+    if ts := out["timestamp"].(float64); int64(ts) != birth.Unix() {
+        t.Errorf("timestamp %v, want the birth moment %d", ts, birth.Unix())
+    }
+    lon := fakeChironLon(julianDay(birth))
+    if sign := out["sign"]; sign != signFromLongitude(lon) {
+        t.Errorf("sign %v, want %s", sign, signFromLongitude(lon))
+    }
+    if deg := out["degree"].(float64); math.Abs(deg-math.Mod(lon, 30)) > 0.005 {
+        t.Errorf("degree %v, want %.2f", deg, math.Mod(lon, 30))
+    }
+    if out["zodiac"] != "tropical" || out["center"] != "geocentric" {
+        t.Errorf("zodiac %v, center %v", out["zodiac"], out["center"])
+    }
+    if speed := out["speed"].(float64); speed != 0.019418 || out["retrograde"] != false {
+        t.Errorf("speed %v, retrograde %v", speed, out["retrograde"])
+    }
+}
+
+func TestChironHandlerHouse(t *testing.T) {
+    for _, system := range []string{"whole_sign", "equal", "placidus"} {
+        t.Run(system, func(t *testing.T) {
+            fake := useFake(t)
+            appConfig.Ephemeris.HouseSystem = system
+            out := postReading(t, londonBirth)
+
+            jd := julianDay(time.Date(1990, 6, 15, 13, 30, 0, 0, time.UTC))
+            cusps, ascmc := make([]float64, 13), make([]float64, 10)
+            if err := fake.HousesEx(jd, 0, 51.5074, -0.1278, 'E', cusps, ascmc); err != nil {
+                t.Fatal(err)
+            }
+            lon, asc := fakeChironLon(jd), ascmc[0]
+            // The fake gives equal houses for every system but whole sign
+            want := int(normDeg(lon-asc)/30) + 1
+            if system == "whole_sign" {
+                want = (int(lon/30)-int(asc/30)+12)%12 + 1
+            }
+            if house := int(out["house"].(float64)); house != want {
+                t.Errorf("house %d, want %d (Chiron %.2f, Ascendant %.2f)", house, want, lon, asc)
+            }
+        })
+    }
+}
+
+func TestChironHandlerRelocatedAngles(t *testing.T) {
+    fake := useFake(t)
+    out := postReading(t, strings.Replace(londonBirth, `"timezone"`, `"relocation_lat": 40.7128, "relocation_lon": -74.006, "timezone"`, 1))
+    relocated, ok := out["relocated"].(map[string]interface{})
+    if !ok {
+        t.Fatalf("relocated = %v", out["relocated"])
+    }
+
+    jd := julianDay(time.Date(1990, 6, 15, 13, 30, 0, 0, time.UTC))
+    cusps, ascmc := make([]float64, 13), make([]float64, 10)
+    if err := fake.HousesEx(jd, 0, 40.7128, -74.006, 'P', cusps, ascmc); err != nil {
+        t.Fatal(err)
+    }
+    for key, want := range map[string]float64{"ascendant": ascmc[0], "midheaven": ascmc[1]} {
+        if got, _ := relocated[key].(float64); math.Abs(got-want) > 1e-6 {
+            t.Errorf("relocated %s %v, want %.6f", key, relocated[key], want)
+        }
+    }
+}
+
+func TestChironHandlerRejectsBadInput(t *testing.T) {
+    useFake(t)
+    for name, body := range map[string]string{
+        "json":     `{"year":`,
+        "timezone": `{"year": 1990, "month": 6, "day": 15, "hour": 12, "timezone": "Mars/Olympus"}`,
+        "zodiac":   `{"year": 1990, "month": 6, "day": 15, "hour": 12, "timezone": "UTC", "zodiac": "draconic"}`,
+    } {
+        r := httptest.NewRequest(http.MethodPost, "/api/chiron", strings.NewReader(body))
+        w := httptest.NewRecorder()
+        chironHandler(w, r)
+        if w.Code != http.StatusBadRequest {
+            t.Errorf("%s: status %d, want 400", name, w.Code)
+        }
+    }
+}
+
+func TestHouseFromCuspsWraparound(t *testing.T) {
+    // Equal houses from 350°, so the first house spans 0°
+    equal := make([]float64, 13)
+    for i := 1; i <= 12; i++ {
+        equal[i] = normDeg(350 + 30*float64(i-1))
+    }
+    // Unequal houses with the 0° crossing inside the fourth
+    unequal := []float64{0, 250, 275, 300, 340, 25, 50, 70, 95, 120, 160, 205, 230}
+
+    cases := []struct {
+        cusps []float64
+        lon   float64
+        want  int
+    }{
+        {equal, 350, 1},
+        {equal, 359.99, 1},
+        {equal, 0, 1},
+        {equal, 19.99, 1},
+        {equal, 20, 2},
+        {equal, 349.99, 12},
+        {equal, 320, 12},
+        {unequal, 250, 1},
+        {unequal, 339.99, 3},
+        {unequal, 340, 4},
+        {unequal, 359.5, 4},
+        {unequal, 0.5, 4},
+        {unequal, 24.99, 4},
+        {unequal, 25, 5},
+        {unequal, 249.99, 12},
+    }
+    for _, c := range cases {
+        if got := houseFromCusps(c.cusps, c.lon); got != c.want {
+            t.Errorf("cusps from %.0f, longitude %.2f: house %d, want %d", c.cusps[1], c.lon, got, c.want)
+        }
+    }
+}
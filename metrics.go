@@ -0,0 +1,227 @@
+package main
+
+import (
+    "bufio"
+    "context"
+    "fmt"
+    "math"
+    "net/http"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+)
+
+// ===== Prometheus metrics =====
+//
+// A small hand-rolled registry writing the Prometheus text exposition
+// format, so the binary doesn't pull in the client library.
+
+type metric interface {
+    writeTo(w *bufio.Writer)
+}
+
+var registry []metric
+
+func escapeLabel(v string) string {
+    v = strings.ReplaceAll(v, `\`, `\\`)
+    v = strings.ReplaceAll(v, "\n", `\n`)
+    return strings.ReplaceAll(v, `"`, `\"`)
+}
+
+func formatLabels(names, values []string, extra ...string) string {
+    if len(names) == 0 && len(extra) == 0 {
+        return ""
+    }
+    parts := make([]string, 0, len(names)+1)
+    for i, n := range names {
+        parts = append(parts, n+`="`+escapeLabel(values[i])+`"`)
+    }
+    for i := 0; i+1 < len(extra); i += 2 {
+        parts = append(parts, extra[i]+`="`+escapeLabel(extra[i+1])+`"`)
+    }
+    return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatFloat(f float64) string {
+    if math.IsInf(f, 1) {
+        return "+Inf"
+    }
+    return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+type counterVec struct {
+    name, help string
+    labels     []string
+
+    mu     sync.Mutex
+    values map[string]float64
+    keys   map[string][]string
+}
+
+func newCounterVec(name, help string, labels ...string) *counterVec {
+    c := &counterVec{name: name, help: help, labels: labels,
+        values: map[string]float64{}, keys: map[string][]string{}}
+    registry = append(registry, c)
+    return c
+}
+
+func (c *counterVec) add(v float64, labelValues ...string) {
+    key := strings.Join(labelValues, "\xff")
+    c.mu.Lock()
+    if _, ok := c.keys[key]; !ok {
+        c.keys[key] = labelValues
+    }
+    c.values[key] += v
+    c.mu.Unlock()
+}
+
+func (c *counterVec) inc(labelValues ...string) {
+    c.add(1, labelValues...)
+}
+
+func (c *counterVec) writeTo(w *bufio.Writer) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+    for _, key := range sortedKeys(c.values) {
+        fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labels, c.keys[key]), formatFloat(c.values[key]))
+    }
+}
+
+type histogramSeries struct {
+    labelValues []string
+    counts      []uint64 // per bucket, not cumulative
+    sum         float64
+    count       uint64
+}
+
+type histogramVec struct {
+    name, help string
+    labels     []string
+    buckets    []float64
+
+    mu     sync.Mutex
+    series map[string]*histogramSeries
+}
+
+var (
+    httpBuckets      = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+    ephemerisBuckets = []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1}
+    arcsecBuckets    = []float64{.01, .1, .5, 1, 2, 5, 10, 30, 60, 300, 3600}
+)
+
+func newHistogramVec(name, help string, buckets []float64, labels ...string) *histogramVec {
+    h := &histogramVec{name: name, help: help, labels: labels, buckets: buckets,
+        series: map[string]*histogramSeries{}}
+    registry = append(registry, h)
+    return h
+}
+
+func (h *histogramVec) observe(v float64, labelValues ...string) {
+    key := strings.Join(labelValues, "\xff")
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    s, ok := h.series[key]
+    if !ok {
+        s = &histogramSeries{labelValues: labelValues, counts: make([]uint64, len(h.buckets))}
+        h.series[key] = s
+    }
+    if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+        s.counts[i]++
+    }
+    s.sum += v
+    s.count++
+}
+
+func (h *histogramVec) writeTo(w *bufio.Writer) {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+    for _, key := range sortedKeys(h.series) {
+        s := h.series[key]
+        var cumulative uint64
+        for i, le := range h.buckets {
+            cumulative += s.counts[i]
+            fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, s.labelValues, "le", formatFloat(le)), cumulative)
+        }
+        fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, s.labelValues, "le", "+Inf"), s.count)
+        fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labels, s.labelValues), formatFloat(s.sum))
+        fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labels, s.labelValues), s.count)
+    }
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+    keys := make([]string, 0, len(m))
+    for k := range m {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    return keys
+}
+
+// ===== Service metrics =====
+
+var (
+    httpRequests = newCounterVec("chiron_http_requests_total",
+        "HTTP requests by route, method and status code.", "route", "method", "code")
+    httpDuration = newHistogramVec("chiron_http_request_duration_seconds",
+        "HTTP request latency by route.", httpBuckets, "route")
+    ephemerisDuration = newHistogramVec("chiron_ephemeris_call_duration_seconds",
+        "Time spent in Swiss Ephemeris calls.", ephemerisBuckets, "call")
+    ephemerisErrors = newCounterVec("chiron_ephemeris_errors_total",
+        "Swiss Ephemeris calls that returned an error.", "call")
+    ephemerisDiscrepancy = newHistogramVec("chiron_ephemeris_discrepancy_arcseconds",
+        "Difference between the primary and comparison ephemeris backends (ephemeris.compare).", arcsecBuckets, "call", "backend")
+    interpretationLookups = newCounterVec("chiron_interpretation_lookups_total",
+        "Interpretation lookups; result is hit when the sign/house pair is in the corpus, miss when the fallback text was used.", "result")
+    readingsBySignHouse = newCounterVec("chiron_readings_total",
+        "Readings returned, by Chiron sign and house.", "sign", "house")
+)
+
+// observeEphemeris records a Swiss Ephemeris call for both /metrics and the
+// request's access log line.
+func observeEphemeris(ctx context.Context, call string, start time.Time) {
+    d := time.Since(start)
+    ephemerisDuration.observe(d.Seconds(), call)
+    addEphemerisTime(ctx, d)
+}
+
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+    bw := bufio.NewWriter(w)
+    for _, m := range registry {
+        m.writeTo(bw)
+    }
+    bw.Flush()
+}
+
+// methodLabel is the method for metric labels; clients can send any method
+// name, so anything unusual counts as "other".
+func methodLabel(method string) string {
+    switch method {
+    case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+        http.MethodDelete, http.MethodOptions, http.MethodConnect, http.MethodTrace:
+        return method
+    }
+    return "other"
+}
+
+// instrumentHandler counts requests and records latency per route pattern,
+// so arbitrary paths can't blow up label cardinality.
+func instrumentHandler(mux *http.ServeMux, next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        start := time.Now()
+        route := routePattern(mux, r)
+
+        rec := &statusRecorder{ResponseWriter: w}
+        next.ServeHTTP(rec, r)
+        if rec.status == 0 {
+            rec.status = http.StatusOK
+        }
+
+        httpRequests.inc(route, methodLabel(r.Method), strconv.Itoa(rec.status))
+        httpDuration.observe(time.Since(start).Seconds(), route)
+    })
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func TestMetricsMethodLabelIsBounded(t *testing.T) {
+    mux := http.NewServeMux()
+    mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {})
+    h := instrumentHandler(mux, mux)
+    for _, method := range []string{"GET", "PROPFIND", "X-RANDOM-1", "X-RANDOM-2"} {
+        h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/livez", nil))
+    }
+
+    w := httptest.NewRecorder()
+    metricsHandler(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+    out := w.Body.String()
+    for _, want := range []string{`method="GET"`, `method="other"`, "chiron_interpretation_lookups_total"} {
+        if !strings.Contains(out, want) {
+            t.Errorf("/metrics lacks %s", want)
+        }
+    }
+    for _, unwanted := range []string{"PROPFIND", "X-RANDOM", "chiron_interpretation_cache_total"} {
+        if strings.Contains(out, unwanted) {
+            t.Errorf("/metrics has %s", unwanted)
+        }
+    }
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+    "math"
+    "net/http"
+    "time"
+)
+
+// ===== Secondary progressions and solar arc =====
+
+const (
+    tropicalYear  = 365.24219 // days; one day after birth progresses one year of life
+    forecastScan  = 30 * 24 * time.Hour
+    forecastRange = 1          // years looked ahead for aspects and ingresses
+    naibodRate    = 0.98564733 // mean solar motion, degrees of right ascension per year for the progressed MC
+)
+
+type progressionRequest struct {
+    BirthData
+    TargetDate string `json:"target_date"` // YYYY-MM-DD, default today
+}
+
+type ProgressedPosition struct {
+    Longitude       float64 `json:"longitude"`
+    Sign            string  `json:"sign"`
+    Degree          float64 `json:"degree"`
+    NatalHouse      int     `json:"natal_house"`                // progressed Chiron in the natal houses
+    ProgressedHouse int     `json:"progressed_house,omitempty"` // in the progressed chart's houses (secondary only)
+}
+
+type ProgressedAngle struct {
+    Longitude float64 `json:"longitude"`
+    Sign      string  `json:"sign"`
+    Degree    float64 `json:"degree"`
+}
+
+// ProgressedEvent is an aspect to a natal point, or a sign or house ingress,
+// that becomes exact within the forecast year.
+type ProgressedEvent struct {
+    Method     string `json:"method"` // secondary or solar_arc
+    Type       string `json:"type"`   // aspect, sign_ingress or house_ingress
+    Aspect     string `json:"aspect,omitempty"`
+    NatalPoint string `json:"natal_point,omitempty"`
+    Sign       string `json:"sign,omitempty"`
+    House      int    `json:"house,omitempty"`
+    Exact      string `json:"exact"` // date, YYYY-MM-DD
+}
+
+type ProgressionReading struct {
+    TargetDate          string             `json:"target_date"`
+    Secondary           ProgressedPosition `json:"secondary"`
+    ProgressedAscendant ProgressedAngle    `json:"progressed_ascendant"`
+    SolarArc            ProgressedPosition `json:"solar_arc"`
+    Arc                 float64            `json:"arc"` // degrees the progressed Sun has moved
+    Events              []ProgressedEvent  `json:"events"`
+    Zodiac              string             `json:"zodiac"`
+    Ayanamsa            string             `json:"ayanamsa,omitempty"`
+}
+
+// progressedJD maps a calendar moment to the secondary-progressed ephemeris day.
+func progressedJD(natalJD float64, t time.Time) float64 {
+    return natalJD + (julianDay(t.UTC())-natalJD)/tropicalYear
+}
+
+func positionOf(lon float64, natalCusps []float64) ProgressedPosition {
+    return ProgressedPosition{
+        Longitude:  round6(lon),
+        Sign:       signFromLongitude(lon),
+        Degree:     math.Round(math.Mod(lon, 30)*100) / 100,
+        NatalHouse: houseFromCusps(natalCusps, lon),
+    }
+}
+
+// scanEvents samples lonAt monthly across [from, to) and reports aspects to
+// the natal points and sign/house ingresses, each bisected to the day.
+func scanEvents(method string, lonAt func(time.Time) (float64, error), from, to time.Time,
+    natal []chartPoint, natalCusps []float64) ([]ProgressedEvent, error) {
+
+    var events []ProgressedEvent
+    prevT := from
+    prev, err := lonAt(from)
+    if err != nil {
+        return nil, err
+    }
+    for prevT.Before(to) {
+        t := prevT.Add(forecastScan)
+        if t.After(to) {
+            t = to
+        }
+        cur, err := lonAt(t)
+        if err != nil {
+            return nil, err
+        }
+
+        // Continuous targets: every aspect angle, either side, to each natal point
+        for _, p := range natal {
+            for _, asp := range majorAspects {
+                for _, side := range []float64{1, -1} {
+                    if side < 0 && (asp.Angle == 0 || asp.Angle == 180) {
+                        continue
+                    }
+                    target := p.Lon + side*asp.Angle
+                    d0, d1 := norm180(prev-target), norm180(cur-target)
+                    if (d0 < 0) == (d1 < 0) || math.Abs(d1-d0) > 90 {
+                        continue
+                    }
+                    at, err := bisectTime(prevT, t, d0, func(t time.Time) (float64, error) {
+                        l, err := lonAt(t)
+                        return norm180(l - target), err
+                    })
+                    if err != nil {
+                        return nil, err
+                    }
+                    events = append(events, ProgressedEvent{Method: method, Type: "aspect", Aspect: asp.Name,
+                        NatalPoint: p.Name, Exact: at.Format("2006-01-02")})
+                }
+            }
+        }
+
+        // Discrete targets: sign and natal house
+        if s0, s1 := int(prev/30), int(cur/30); s0 != s1 {
+            boundary := float64(s1) * 30
+            if norm180(cur-prev) < 0 {
+                boundary = float64(s0) * 30
+            }
+            at, err := bisectTime(prevT, t, norm180(prev-boundary), func(t time.Time) (float64, error) {
+                l, err := lonAt(t)
+                return norm180(l - boundary), err
+            })
+            if err != nil {
+                return nil, err
+            }
+            events = append(events, ProgressedEvent{Method: method, Type: "sign_ingress", Sign: signFromLongitude(cur), Exact: at.Format("2006-01-02")})
+        }
+        if h0, h1 := houseFromCusps(natalCusps, prev), houseFromCusps(natalCusps, cur); h0 != h1 {
+            cusp := natalCusps[h1]
+            if norm180(cur-prev) < 0 {
+                cusp = natalCusps[h0]
+            }
+            at, err := bisectTime(prevT, t, norm180(prev-cusp), func(t time.Time) (float64, error) {
+                l, err := lonAt(t)
+                return norm180(l - cusp), err
+            })
+            if err != nil {
+                return nil, err
+            }
+            events = append(events, ProgressedEvent{Method: method, Type: "house_ingress", House: h1, Exact: at.Format("2006-01-02")})
+        }
+
+        prevT, prev = t, cur
+    }
+    return events, nil
+}
+
+// progressedHouses advances the natal ARMC by the Naibod rate and casts the
+// houses from it, so the progressed angles move about a degree a year rather
+// than with the clock time of the progressed day.
+func progressedHouses(natalJD, progJD, years, lat, lon, chironLon float64, opts calcOptions) (placement, error) {
+    armc, err := computeARMC(natalJD, lat, lon)
+    if err != nil {
+        return placement{}, err
+    }
+    return placeFromARMC(progJD, math.Mod(armc+years*naibodRate, 360), lat, chironLon, opts)
+}
+
+// bisectTime narrows [a, b] to a day around the zero of f, given f(a) = fa.
+func bisectTime(a, b time.Time, fa float64, f func(time.Time) (float64, error)) (time.Time, error) {
+    for b.Sub(a) > 24*time.Hour {
+        mid := a.Add(b.Sub(a) / 2)
+        fm, err := f(mid)
+        if err != nil {
+            return time.Time{}, err
+        }
+        if (fm < 0) == (fa < 0) {
+            a, fa = mid, fm
+        } else {
+            b = mid
+        }
+    }
+    return a.Add(b.Sub(a) / 2), nil
+}
+
+func progressionsHandler(w http.ResponseWriter, r *http.Request) {
+    var req progressionRequest
+    if err := decodeJSON(w, r, &req); err != nil {
+        writeDecodeError(w, err)
+        return
+    }
+    if req.UnknownTime {
+        http.Error(w, "progressions need a birth time", http.StatusBadRequest)
+        return
+    }
+    utc, _, err := birthTime(req.BirthData)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    target := time.Now().UTC().Truncate(24 * time.Hour)
+    if req.TargetDate != "" {
+        if target, err = time.Parse("2006-01-02", req.TargetDate); err != nil {
+            http.Error(w, "target_date must be YYYY-MM-DD", http.StatusBadRequest)
+            return
+        }
+    }
+    if target.Before(utc) {
+        http.Error(w, "target_date is before birth", http.StatusBadRequest)
+        return
+    }
+    opts, err := resolveCalcOptions(req.BirthData)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    ctx := r.Context()
+    natalJD := julianDay(utc)
+    ephStart := time.Now()
+    natalChiron, err := computeChironLongitude(natalJD, opts)
+    var natalPoints []chartPoint
+    if err == nil {
+        natalPoints, err = computeChartPoints(natalJD, opts)
+    }
+    observeEphemeris(ctx, "progressions", ephStart)
+    if err != nil {
+        http.Error(w, "ephemeris unavailable", http.StatusInternalServerError)
+        return
+    }
+    natal, err := placeInHouses(ctx, natalJD, req.Lat, req.Lon, natalChiron, opts)
+    if err != nil {
+        http.Error(w, "house calculation failed", http.StatusInternalServerError)
+        return
+    }
+    natalSun := natalPoints[0].Lon
+    natalPoints = append(natalPoints,
+        chartPoint{"Chiron", natalChiron}, chartPoint{"Ascendant", natal.Asc}, chartPoint{"Midheaven", natal.MC})
+
+    secondaryAt := func(t time.Time) (float64, error) {
+        return computeChironLongitude(progressedJD(natalJD, t), opts)
+    }
+    solarArcAt := func(t time.Time) (float64, error) {
+        sun, err := computeBodyLongitude(progressedJD(natalJD, t), SE_SUN, opts)
+        return math.Mod(natalChiron+norm180(sun-natalSun)+360, 360), err
+    }
+
+    ephStart = time.Now()
+    progJD := progressedJD(natalJD, target)
+    secondary, err := secondaryAt(target)
+    if err != nil {
+        http.Error(w, "ephemeris unavailable", http.StatusInternalServerError)
+        return
+    }
+    solarArc, err := solarArcAt(target)
+    if err != nil {
+        http.Error(w, "ephemeris unavailable", http.StatusInternalServerError)
+        return
+    }
+    years := (julianDay(target) - natalJD) / tropicalYear
+    progressed, err := progressedHouses(natalJD, progJD, years, req.Lat, req.Lon, secondary, opts)
+    if err != nil {
+        http.Error(w, "house calculation failed", http.StatusInternalServerError)
+        return
+    }
+
+    end := target.AddDate(forecastRange, 0, 0)
+    events, err := scanEvents("secondary", secondaryAt, target, end, natalPoints, natal.Cusps)
+    if err == nil {
+        var sa []ProgressedEvent
+        sa, err = scanEvents("solar_arc", solarArcAt, target, end, natalPoints, natal.Cusps)
+        events = append(events, sa...)
+    }
+    observeEphemeris(ctx, "progressions", ephStart)
+    if err != nil {
+        http.Error(w, "ephemeris unavailable", http.StatusInternalServerError)
+        return
+    }
+    if events == nil {
+        events = []ProgressedEvent{}
+    }
+
+    resp := ProgressionReading{
+        TargetDate: target.Format("2006-01-02"),
+        Secondary:  positionOf(secondary, natal.Cusps),
+        ProgressedAscendant: ProgressedAngle{
+            Longitude: round6(progressed.Asc),
+            Sign:      signFromLongitude(progressed.Asc),
+            Degree:    math.Round(math.Mod(progressed.Asc, 30)*100) / 100,
+        },
+        SolarArc: positionOf(solarArc, natal.Cusps),
+        Arc:      round6(math.Mod(solarArc-natalChiron+360, 360)),
+        Events:   events,
+        Zodiac:   opts.Zodiac,
+        Ayanamsa: opts.Ayanamsa,
+    }
+    resp.Secondary.ProgressedHouse = progressed.House
+    writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+    "encoding/json"
+    "math"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+func postProgressions(t *testing.T, target string) ProgressionReading {
+    t.Helper()
+    body := `{"year": 1990, "month": 6, "day": 15, "hour": 14.5, "timezone": "Europe/London", "target_date": "` + target + `"}`
+    w := httptest.NewRecorder()
+    progressionsHandler(w, httptest.NewRequest(http.MethodPost, "/api/progressions", strings.NewReader(body)))
+    var out ProgressionReading
+    if err := json.Unmarshal(w.Body.Bytes(), &out); w.Code != http.StatusOK || err != nil {
+        t.Fatalf("status %d %q", w.Code, strings.TrimSpace(w.Body.String()))
+    }
+    return out
+}
+
+func TestProgressionsKnownPositions(t *testing.T) {
+    useFake(t)
+    natal := time.Date(1990, 6, 15, 13, 30, 0, 0, time.UTC)
+    natalJD := julianDay(natal)
+    for _, target := range []string{"1990-06-16", "2000-01-01", "2024-06-15", "2060-12-31"} {
+        at, _ := time.Parse("2006-01-02", target)
+        got := postProgressions(t, target)
+        // The fake Sun moves at a constant rate, so the arc is exact
+        years := (julianDay(at) - natalJD) / tropicalYear
+        arc := fakeOrbits[SE_SUN][1] * years
+        for name, c := range map[string][2]float64{
+            "secondary": {got.Secondary.Longitude, fakeChironLon(progressedJD(natalJD, at))},
+            "arc":       {got.Arc, arc},
+            "solar arc": {got.SolarArc.Longitude, normDeg(fakeChironLon(natalJD) + arc)},
+        } {
+            if math.Abs(norm180(c[0]-c[1])) > 1e-5 {
+                t.Errorf("%s: %s %.6f, want %.6f", target, name, c[0], c[1])
+            }
+        }
+    }
+}
+
+func TestProgressionsSignIngressDate(t *testing.T) {
+    useFake(t)
+    natalJD := julianDay(time.Date(1990, 6, 15, 13, 30, 0, 0, time.UTC))
+    // Solar-arc Chiron reaches 0° Scorpio once the Sun has moved that far
+    years := (210 - fakeChironLon(natalJD)) / fakeOrbits[SE_SUN][1]
+    want := jdToTime(natalJD + years*tropicalYear)
+    got := postProgressions(t, want.AddDate(0, -6, 0).Format("2006-01-02"))
+    for _, e := range got.Events {
+        if e.Method != "solar_arc" || e.Type != "sign_ingress" {
+            continue
+        }
+        exact, _ := time.Parse("2006-01-02", e.Exact)
+        if e.Sign != "Scorpio" || exact.Sub(want).Abs() > 36*time.Hour {
+            t.Errorf("ingress into %s on %s, want Scorpio on %s", e.Sign, e.Exact, want.Format("2006-01-02"))
+        }
+        return
+    }
+    t.Errorf("no solar-arc sign ingress in %+v", got.Events)
+}
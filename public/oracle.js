@@ -0,0 +1,30 @@
+// Loads chiron.wasm, the WebAssembly build of the oracle, so readings are
+// computed on this device and birth data never leaves the browser.
+// loadOracle() resolves to the chironOracle API with errors thrown rather
+// than returned as {error}.
+let oraclePromise;
+
+function loadOracle() {
+  if (!oraclePromise) {
+    oraclePromise = (async () => {
+      const go = new Go(); // from wasm_exec.js
+      const { instance } = await WebAssembly.instantiateStreaming(fetch("/chiron.wasm"), go.importObject);
+      go.run(instance);
+      const raw = globalThis.chironOracle;
+      const api = { backend: raw.backend };
+      for (const name of ["reading", "julianDay", "chironLongitude", "houseCusps", "interpretation"]) {
+        api[name] = (...args) => {
+          const result = raw[name](...args);
+          if (result && typeof result === "object" && "error" in result) {
+            throw new Error(result.error);
+          }
+          return result;
+        };
+      }
+      return api;
+    })();
+    // A failed load can be retried
+    oraclePromise.catch(() => { oraclePromise = undefined; });
+  }
+  return oraclePromise;
+}
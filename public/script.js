@@ -1,15 +1,41 @@
-document.getElementById("oracle-form").addEventListener("submit", function(e) {
+document.getElementById("timezone").value = Intl.DateTimeFormat().resolvedOptions().timeZone;
+
+document.getElementById("oracle-form").addEventListener("submit", async function(e) {
   e.preventDefault();
-  const city = document.getElementById("city").value;
-  const date = document.getElementById("date").value;
-  const time = document.getElementById("time").value;
+  const [year, month, day] = document.getElementById("date").value.split("-").map(Number);
+  const [hours, minutes] = document.getElementById("time").value.split(":").map(Number);
+  const birth = {
+    year, month, day,
+    hour: hours + minutes / 60,
+    lat: parseFloat(document.getElementById("lat").value),
+    lon: parseFloat(document.getElementById("lon").value),
+    timezone: document.getElementById("timezone").value,
+  };
 
   const resultBox = document.getElementById("result");
-  resultBox.innerHTML = `
-    <p>🌌 Oracle received:</p>
-    <p><strong>City:</strong> ${city}</p>
-    <p><strong>Date:</strong> ${date}</p>
-    <p><strong>Time:</strong> ${time}</p>
-    <p>✨ Your interpretation will appear here soon...</p>
-  `;
+  resultBox.innerHTML = "<p>🌌 Consulting the oracle...</p>";
+  try {
+    const reading = document.getElementById("local").checked
+      ? (await loadOracle()).reading(birth)
+      : await fetchReading(birth);
+    resultBox.innerHTML = `
+      <p><strong>Chiron:</strong> ${reading.degree}° ${reading.sign}, house ${reading.house}</p>
+      <p><strong>Wound:</strong> ${reading.traditional_wound}</p>
+      <p><strong>Strength:</strong> ${reading.lhp_strength}</p>
+    `;
+  } catch (err) {
+    resultBox.textContent = `The oracle is silent: ${err.message}`;
+  }
 });
+
+async function fetchReading(birth) {
+  const response = await fetch("/api/chiron", {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(birth),
+  });
+  if (!response.ok) {
+    throw new Error((await response.text()).trim());
+  }
+  return response.json();
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+    "fmt"
+    "math"
+    "net"
+    "net/http"
+    "net/netip"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+)
+
+// ===== Rate limiting =====
+
+type tokenBucket struct {
+    tokens float64
+    last   time.Time
+}
+
+// rateLimiter is a per-client-IP token bucket.
+type rateLimiter struct {
+    rate  float64 // tokens per second
+    burst float64
+
+    mu        sync.Mutex
+    buckets   map[string]*tokenBucket
+    lastSweep time.Time
+}
+
+func newRateLimiter(cfg rateLimitConfig) *rateLimiter {
+    return &rateLimiter{
+        rate:    cfg.RequestsPerSecond,
+        burst:   float64(cfg.Burst),
+        buckets: map[string]*tokenBucket{},
+    }
+}
+
+// allow takes a token for key, or reports how long until one is available.
+func (l *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    l.sweep(now)
+    b, ok := l.buckets[key]
+    if !ok {
+        b = &tokenBucket{tokens: l.burst, last: now}
+        l.buckets[key] = b
+    }
+    b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+    b.last = now
+    if b.tokens >= 1 {
+        b.tokens--
+        return true, 0
+    }
+    wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+    return false, wait
+}
+
+// sweep drops buckets that have refilled completely, so idle clients don't
+// accumulate. Runs at most once a minute.
+func (l *rateLimiter) sweep(now time.Time) {
+    if now.Sub(l.lastSweep) < time.Minute {
+        return
+    }
+    l.lastSweep = now
+    full := time.Duration(l.burst / l.rate * float64(time.Second))
+    for k, b := range l.buckets {
+        if now.Sub(b.last) > full {
+            delete(l.buckets, k)
+        }
+    }
+}
+
+// parseTrustedProxies reads IP addresses and CIDR ranges.
+func parseTrustedProxies(list []string) ([]netip.Prefix, error) {
+    var prefixes []netip.Prefix
+    for _, s := range list {
+        if p, err := netip.ParsePrefix(s); err == nil {
+            prefixes = append(prefixes, p.Masked())
+            continue
+        }
+        a, err := netip.ParseAddr(s)
+        if err != nil {
+            return nil, fmt.Errorf("%q is neither an IP address nor a CIDR range", s)
+        }
+        prefixes = append(prefixes, netip.PrefixFrom(a.Unmap(), a.Unmap().BitLen()))
+    }
+    return prefixes, nil
+}
+
+func trusted(a netip.Addr, proxies []netip.Prefix) bool {
+    for _, p := range proxies {
+        if p.Contains(a) {
+            return true
+        }
+    }
+    return false
+}
+
+// clientIP is the peer address, or, when the peer is a trusted proxy, the
+// client it reports: CF-Connecting-IP, else the nearest X-Forwarded-For hop
+// that isn't itself a trusted proxy. Headers from other peers are ignored,
+// since anyone can send them.
+func clientIP(r *http.Request, proxies []netip.Prefix) string {
+    host, _, err := net.SplitHostPort(r.RemoteAddr)
+    if err != nil {
+        host = r.RemoteAddr
+    }
+    peer, err := netip.ParseAddr(host)
+    if err != nil || !trusted(peer.Unmap(), proxies) {
+        return host
+    }
+    if a, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("CF-Connecting-IP"))); err == nil {
+        return a.Unmap().String()
+    }
+    hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+    for i := len(hops) - 1; i >= 0; i-- {
+        a, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+        if err != nil {
+            break // can't see past a malformed hop
+        }
+        if !trusted(a.Unmap(), proxies) {
+            return a.Unmap().String()
+        }
+    }
+    return host
+}
+
+var rateLimited = newCounterVec("chiron_rate_limited_total",
+    "API requests rejected by the per-client rate limit.")
+
+// rateLimitMiddleware limits /api/* requests per client IP. A zero rate
+// disables it.
+func rateLimitMiddleware(cfg rateLimitConfig, next http.Handler) http.Handler {
+    if cfg.RequestsPerSecond <= 0 {
+        return next
+    }
+    limiter := newRateLimiter(cfg)
+    proxies, _ := parseTrustedProxies(cfg.TrustedProxies) // checked by validate
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if !strings.HasPrefix(r.URL.Path, "/api/") || r.Method == http.MethodOptions {
+            next.ServeHTTP(w, r)
+            return
+        }
+        if ok, wait := limiter.allow(clientIP(r, proxies), time.Now()); !ok {
+            rateLimited.inc()
+            w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+            http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+            return
+        }
+        next.ServeHTTP(w, r)
+    })
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+    h := rateLimitMiddleware(rateLimitConfig{RequestsPerSecond: 1, Burst: 2},
+        http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+    get := func(path, addr string) *httptest.ResponseRecorder {
+        r := httptest.NewRequest(http.MethodGet, path, nil)
+        r.RemoteAddr = addr
+        w := httptest.NewRecorder()
+        h.ServeHTTP(w, r)
+        return w
+    }
+    for i := 0; i < 2; i++ {
+        if w := get("/api/ayanamsas", "192.0.2.1:1000"); w.Code != http.StatusOK {
+            t.Fatalf("request %d within the burst: %d", i+1, w.Code)
+        }
+    }
+    w := get("/api/ayanamsas", "192.0.2.1:1001")
+    if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+        t.Errorf("over the burst: %d, Retry-After %q", w.Code, w.Header().Get("Retry-After"))
+    }
+    if w := get("/api/ayanamsas", "192.0.2.2:1000"); w.Code != http.StatusOK {
+        t.Errorf("another client limited: %d", w.Code)
+    }
+    if w := get("/livez", "192.0.2.1:1000"); w.Code != http.StatusOK {
+        t.Errorf("non-API route limited: %d", w.Code)
+    }
+}
+
+func TestClientIP(t *testing.T) {
+    proxies, err := parseTrustedProxies([]string{"10.0.0.0/8", "198.51.100.7"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    cases := []struct {
+        name, peer, xff, cf, want string
+    }{
+        {"direct", "192.0.2.1:1000", "", "", "192.0.2.1"},
+        {"untrusted peer's headers ignored", "192.0.2.1:1000", "203.0.113.9", "203.0.113.8", "192.0.2.1"},
+        {"trusted CF-Connecting-IP", "10.1.2.3:1000", "203.0.113.9", "203.0.113.8", "203.0.113.8"},
+        {"trusted X-Forwarded-For", "198.51.100.7:1000", "203.0.113.9", "", "203.0.113.9"},
+        {"spoofed first hop skipped", "10.1.2.3:1000", "6.6.6.6, 203.0.113.9, 10.4.4.4", "", "203.0.113.9"},
+        {"malformed hop stops the walk", "10.1.2.3:1000", "203.0.113.9, junk", "", "10.1.2.3"},
+        {"only proxies", "10.1.2.3:1000", "10.9.9.9", "", "10.1.2.3"},
+        {"IPv6", "[2001:db8::1]:1000", "203.0.113.9", "", "2001:db8::1"},
+    }
+    for _, c := range cases {
+        r := httptest.NewRequest(http.MethodGet, "/api/ayanamsas", nil)
+        r.RemoteAddr = c.peer
+        if c.xff != "" {
+            r.Header.Set("X-Forwarded-For", c.xff)
+        }
+        if c.cf != "" {
+            r.Header.Set("CF-Connecting-IP", c.cf)
+        }
+        if got := clientIP(r, proxies); got != c.want {
+            t.Errorf("%s: %s, want %s", c.name, got, c.want)
+        }
+    }
+    if _, err := parseTrustedProxies([]string{"cloudflare"}); err == nil {
+        t.Error("a name accepted as a trusted proxy")
+    }
+}
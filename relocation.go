@@ -0,0 +1,148 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "math"
+    "time"
+)
+
+// ===== Houses and relocation =====
+
+// placement is Chiron's house for one observer location.
+type placement struct {
+    House   int
+    Cusps   []float64 // 1..12 used
+    Asc, MC float64
+}
+
+// placeInHouses computes the angles at lat/lon and puts chironLon in a house
+// of the configured system.
+func placeInHouses(ctx context.Context, jd, lat, lon, chironLon float64, opts calcOptions) (placement, error) {
+    ephStart := time.Now()
+    asc, mc, err := computeAngles(jd, lat, lon, opts)
+    observeEphemeris(ctx, "houses", ephStart)
+    if err != nil {
+        return placement{}, err
+    }
+    ascSignIndex := int(math.Floor(asc/30.0)) % 12
+    p := placement{
+        House: wholeSignHouse(ascSignIndex, chironLon),
+        Cusps: wholeSignCusps(ascSignIndex),
+        Asc:   asc,
+        MC:    mc,
+    }
+    if hsys := houseSystems[appConfig.Ephemeris.HouseSystem]; hsys != 'W' {
+        ephStart = time.Now()
+        p.Cusps, err = computeHouseCusps(jd, lat, lon, hsys, opts)
+        observeEphemeris(ctx, "houses", ephStart)
+        if err != nil {
+            return placement{}, err
+        }
+        p.House = houseFromCusps(p.Cusps, chironLon)
+    }
+    return p, nil
+}
+
+// computeARMC returns the sidereal time at lat/lon, in degrees.
+func computeARMC(jd, lat, lon float64) (float64, error) {
+    cusps := make([]float64, 13)
+    ascmc := make([]float64, 10)
+    var err error
+    withEphemeris(tropical, func() {
+        err = eph.HousesEx(jd, 0, lat, lon, 'W', cusps, ascmc)
+    })
+    if err != nil {
+        ephemerisErrors.inc("houses")
+        return 0, errors.New("sidereal time calculation failed")
+    }
+    return ascmc[2], nil
+}
+
+// computeObliquity returns the true obliquity of the ecliptic at jd.
+func computeObliquity(jd float64) (float64, error) {
+    xx := make([]float64, 6)
+    var err error
+    withEphemeris(tropical, func() {
+        err = eph.CalcUt(jd, SE_ECL_NUT, 0, xx)
+    })
+    if err != nil {
+        ephemerisErrors.inc("houses")
+        return 0, err
+    }
+    return xx[0], nil
+}
+
+// placeFromARMC casts the configured house system from a given ARMC rather
+// than a moment and place, for charts that have no real sky behind them
+// (progressed and composite charts). jd sets the obliquity and ayanamsa.
+func placeFromARMC(jd, armc, lat, chironLon float64, opts calcOptions) (placement, error) {
+    ayanamsa, err := computeAyanamsa(jd, opts)
+    if err != nil {
+        return placement{}, err
+    }
+    eps, err := computeObliquity(jd)
+    if err != nil {
+        return placement{}, err
+    }
+    cusps := make([]float64, 13)
+    ascmc := make([]float64, 10)
+    withEphemeris(tropical, func() {
+        err = eph.HousesArmc(armc, lat, eps, int(houseSystems[appConfig.Ephemeris.HouseSystem]), cusps, ascmc)
+    })
+    if err != nil {
+        ephemerisErrors.inc("houses")
+        return placement{}, fmt.Errorf("house system %s failed at latitude %.2f", appConfig.Ephemeris.HouseSystem, lat)
+    }
+    // HousesArmc is always tropical
+    for i := 1; i <= 12; i++ {
+        cusps[i] = math.Mod(cusps[i]-ayanamsa+360, 360)
+    }
+    p := placement{
+        Cusps: cusps,
+        Asc:   math.Mod(ascmc[0]-ayanamsa+360, 360),
+        MC:    math.Mod(ascmc[1]-ayanamsa+360, 360),
+    }
+    p.House = houseFromCusps(cusps, chironLon)
+    return p, nil
+}
+
+type RelocatedReading struct {
+    Lat              float64        `json:"lat"`
+    Lon              float64        `json:"lon"`
+    House            int            `json:"house"`
+    HouseChanged     bool           `json:"house_changed"` // differs from the natal house
+    Ascendant        float64        `json:"ascendant"`
+    Midheaven        float64        `json:"midheaven"`
+    TraditionalWound string         `json:"traditional_wound"`
+    LHPStrength      string         `json:"lhp_strength"`
+    Angles           []AngleContact `json:"angles,omitempty"`
+    CuspInfluence    *CuspInfluence `json:"cusp_influence,omitempty"`
+}
+
+func newRelocatedReading(lat, lon, chironLon float64, sign string, natalHouse int, p placement) *RelocatedReading {
+    wound, strength := getInterpretation(sign, p.House)
+    return &RelocatedReading{
+        Lat:              lat,
+        Lon:              lon,
+        House:            p.House,
+        HouseChanged:     p.House != natalHouse,
+        Ascendant:        round6(p.Asc),
+        Midheaven:        round6(p.MC),
+        TraditionalWound: wound,
+        LHPStrength:      strength,
+        Angles:           findAngleContacts(chironLon, p.Asc, p.MC, appConfig.Ephemeris.AngleOrb),
+        CuspInfluence:    findCuspInfluence(chironLon, p.House, p.Cusps, sign, appConfig.Ephemeris.CuspOrb),
+    }
+}
+
+func validateRelocation(req BirthData) error {
+    if (req.RelocationLat == nil) != (req.RelocationLon == nil) {
+        return errors.New("relocation_lat and relocation_lon must be given together")
+    }
+    if req.RelocationLat != nil && (math.Abs(*req.RelocationLat) > 90 || math.Abs(*req.RelocationLon) > 180) {
+        return errors.New("relocation_lat/relocation_lon out of range")
+    }
+    return nil
+}
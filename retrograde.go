@@ -0,0 +1,185 @@
+package main
+
+import (
+    "math"
+    "time"
+)
+
+// ===== Retrograde motion and stations =====
+
+const (
+    stationScanStep  = 4.0        // days between speed samples when looking for a sign change
+    stationScanSpan  = 400.0      // Chiron stations twice a year, so one always falls inside this
+    stationPrecision = 1.0 / 1440 // one minute, in days
+)
+
+type Station struct {
+    Date      string  `json:"date"` // RFC 3339, UTC
+    Type      string  `json:"type"` // "retrograde" (turning backwards) or "direct"
+    Longitude float64 `json:"longitude"`
+    DaysAway  float64 `json:"days_away"` // negative for a past station
+}
+
+// chironSpeedAt returns longitude and daily speed. The caller must already
+// be inside withEphemeris.
+func chironSpeedAt(jd float64, flags int) (lon, speed float64, err error) {
+    if lon, speed, ok := chironFromTable(jd, flags); ok {
+        return lon, speed, nil
+    }
+    return bodySpeedAt(jd, SE_CHIRON, flags)
+}
+
+// findStation scans from jd in direction dir (+1 or -1) for the next moment
+// Chiron's speed changes sign, then bisects it down to stationPrecision.
+// It returns nil if there is none within stationScanSpan (always the case
+// for heliocentric positions).
+func findStation(jd float64, dir float64, flags int) (*Station, error) {
+    _, s0, err := chironSpeedAt(jd, flags)
+    if err != nil {
+        return nil, err
+    }
+    from := jd
+    for t := stationScanStep; t <= stationScanSpan; t += stationScanStep {
+        to := jd + dir*t
+        _, s1, err := chironSpeedAt(to, flags)
+        if err != nil {
+            return nil, err
+        }
+        if (s0 < 0) != (s1 < 0) {
+            // Name the station after the motion that follows it
+            later := s1
+            if dir < 0 {
+                later = s0
+            }
+            turning := "direct"
+            if later < 0 {
+                turning = "retrograde"
+            }
+            a, b, sa := from, to, s0
+            for math.Abs(b-a) > stationPrecision {
+                mid := (a + b) / 2
+                _, sm, err := chironSpeedAt(mid, flags)
+                if err != nil {
+                    return nil, err
+                }
+                if (sm < 0) == (sa < 0) {
+                    a, sa = mid, sm
+                } else {
+                    b = mid
+                }
+            }
+            at := (a + b) / 2
+            lon, _, err := chironSpeedAt(at, flags)
+            if err != nil {
+                return nil, err
+            }
+            return &Station{
+                Date:      jdToTime(at).Format(time.RFC3339),
+                Type:      turning,
+                Longitude: round6(lon),
+                DaysAway:  math.Round((at-jd)*100) / 100,
+            }, nil
+        }
+        from, s0 = to, s1
+    }
+    return nil, nil
+}
+
+// computeStations finds the stations either side of jd.
+func computeStations(jd float64, opts calcOptions) (prev, next *Station, err error) {
+    withEphemeris(opts, func() {
+        if prev, err = findStation(jd, -1, opts.flags()); err != nil {
+            return
+        }
+        next, err = findStation(jd, +1, opts.flags())
+    })
+    if err != nil {
+        ephemerisErrors.inc("stations")
+    }
+    return prev, next, err
+}
+
+// isStationary reports whether either station lies within window days.
+func isStationary(prev, next *Station, window float64) bool {
+    for _, s := range []*Station{prev, next} {
+        if s != nil && math.Abs(s.DaysAway) <= window {
+            return true
+        }
+    }
+    return false
+}
+
+// jdToTime is the inverse of julianDay for UT Julian Days.
+func jdToTime(jd float64) time.Time {
+    const unixEpochJD = 2440587.5
+    ms := math.Round((jd - unixEpochJD) * 86400000)
+    return time.UnixMilli(int64(ms)).UTC()
+}
+
+// --- Retrograde interpretations ---
+
+// Natal retrograde Chiron turns the wound inward: it is worked through
+// privately and often recognised late. These texts replace nothing; they
+// are returned alongside the sign × house reading.
+var retrogradeInterpretations = map[string][2]string{
+    "Aries": {
+        "With Chiron retrograde in Aries, the wound around identity turns inward. You may doubt your right to exist long before anyone challenges you, fighting battles only you can see.",
+        "Inverted, your courage is forged in private. You become the warrior who has already faced the inner enemy, and you act from a self that no outside opinion can unseat.",
+    },
+    "Taurus": {
+        "With Chiron retrograde in Taurus, worth feels like something you must quietly earn from yourself. Security never seems solid enough, however much you gather.",
+        "Inverted, you discover a value that cannot be taken away. You build stability from within, and your calm becomes a resource others draw on.",
+    },
+    "Gemini": {
+        "With Chiron retrograde in Gemini, the wound sits in the inner voice. You may rehearse conversations endlessly or silence yourself before a word is spoken.",
+        "Inverted, you master the language of the psyche. Your words carry weight because you have tested them against your own doubts first.",
+    },
+    "Cancer": {
+        "With Chiron retrograde in Cancer, the longing for belonging is carried silently. You may nurture everyone while feeling unheld yourself.",
+        "Inverted, you learn to be your own shelter. From that inner home you offer care without losing yourself, and others feel the difference.",
+    },
+    "Leo": {
+        "With Chiron retrograde in Leo, the wound is a hidden fear of being seen. You may shine for others while privately doubting that your light is real.",
+        "Inverted, your radiance no longer needs an audience. You create and express from an inner fire that applause cannot feed or starve.",
+    },
+    "Virgo": {
+        "With Chiron retrograde in Virgo, the inner critic is relentless. You may measure yourself against a perfection that no one else even sees.",
+        "Inverted, you turn precision into self-knowledge. Your discernment heals because it has first been turned, gently, on yourself.",
+    },
+    "Libra": {
+        "With Chiron retrograde in Libra, the wound lives in your inner sense of fairness. You may give endlessly in relationships while feeling you never deserve balance.",
+        "Inverted, you find equilibrium inside before seeking it in others. Your partnerships become choices rather than needs.",
+    },
+    "Scorpio": {
+        "With Chiron retrograde in Scorpio, the depths are explored alone. You may hold secrets, even from yourself, and fear what surfaces when you let go.",
+        "Inverted, you become a guide to the underworld because you have walked it without a map. Your power is the calm of someone who has survived their own shadow.",
+    },
+    "Sagittarius": {
+        "With Chiron retrograde in Sagittarius, the search for meaning turns inward. Borrowed beliefs never fit, and you may feel spiritually homeless.",
+        "Inverted, you write your own philosophy from lived experience. Your truth is unshakeable because no one handed it to you.",
+    },
+    "Capricorn": {
+        "With Chiron retrograde in Capricorn, the wound is a private sense of never having achieved enough. Authority may feel like something you secretly lack.",
+        "Inverted, you become your own authority. You build structures that reflect inner integrity rather than outside expectation.",
+    },
+    "Aquarius": {
+        "With Chiron retrograde in Aquarius, alienation is felt from the inside. You may feel like an outsider even among those who share your ideals.",
+        "Inverted, your difference becomes your gift. You belong to the future you are building, and you welcome others who feel out of place.",
+    },
+    "Pisces": {
+        "With Chiron retrograde in Pisces, boundaries dissolve inwardly. You may absorb pain that is not yours and struggle to know where you end.",
+        "Inverted, you learn to swim the depths without drowning. Your compassion becomes a chosen practice rather than an open wound.",
+    },
+}
+
+type Interpretation struct {
+    TraditionalWound string `json:"traditional_wound"`
+    LHPStrength      string `json:"lhp_strength"`
+}
+
+func getRetrogradeInterpretation(sign string) *Interpretation {
+    if pair, ok := retrogradeInterpretations[sign]; ok {
+        return &Interpretation{TraditionalWound: pair[0], LHPStrength: pair[1]}
+    }
+    return nil
+}
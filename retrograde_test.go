@@ -0,0 +1,97 @@
+package main
+
+import (
+    "math"
+    "testing"
+    "time"
+)
+
+// wobblingEphemeris is the fake with Chiron swinging back and forth, so its
+// stations fall at known moments: lon = wobbleCentre + wobbleAmp·sin(2π(jd-J2000)/wobblePeriod).
+type wobblingEphemeris struct{ fakeEphemeris }
+
+const (
+    wobbleCentre = 100.0
+    wobbleAmp    = 5.0
+    wobblePeriod = 200.0 // days; stations a quarter period either side of each extreme
+)
+
+func (w *wobblingEphemeris) CalcUt(jd float64, body, flags int, xx []float64) error {
+    if err := w.fakeEphemeris.CalcUt(jd, body, flags, xx); err != nil || body != SE_CHIRON {
+        return err
+    }
+    omega := 2 * math.Pi / wobblePeriod
+    xx[0] = wobbleCentre + wobbleAmp*math.Sin(omega*(jd-2451545))
+    xx[3] = wobbleAmp * omega * math.Cos(omega*(jd-2451545))
+    return nil
+}
+
+func TestComputeStations(t *testing.T) {
+    useEphemeris(t, &wobblingEphemeris{})
+    // Moving forwards, 10 days after the wobble's centre crossing
+    jd := 2451545 + 10.0
+    prev, next, err := computeStations(jd, tropical)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if prev == nil || next == nil {
+        t.Fatalf("stations %v, %v; want both", prev, next)
+    }
+    check := func(s *Station, typ string, days, lon float64) {
+        t.Helper()
+        if s.Type != typ {
+            t.Errorf("%s station typed %q", typ, s.Type)
+        }
+        if math.Abs(s.DaysAway-days) > 2*stationPrecision {
+            t.Errorf("%s station %.5f days away, want %.5f", typ, s.DaysAway, days)
+        }
+        if math.Abs(s.Longitude-lon) > 1e-4 {
+            t.Errorf("%s station at %.5f°, want %.5f°", typ, s.Longitude, lon)
+        }
+    }
+    // Forward motion ends at the top of the swing and began at the bottom
+    check(next, "retrograde", wobblePeriod/4-10, wobbleCentre+wobbleAmp)
+    check(prev, "direct", -wobblePeriod/4-10, wobbleCentre-wobbleAmp)
+
+    if !isStationary(prev, next, 45) || isStationary(prev, next, 35) {
+        t.Errorf("isStationary wrong for a station %.1f days away", next.DaysAway)
+    }
+}
+
+func TestComputeStationsNoneForSteadyMotion(t *testing.T) {
+    useFake(t)
+    prev, next, err := computeStations(2451545, tropical)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if prev != nil || next != nil {
+        t.Errorf("stations %v, %v for a body that never turns", prev, next)
+    }
+}
+
+func TestChironHandlerRetrograde(t *testing.T) {
+    useEphemeris(t, &wobblingEphemeris{})
+    // 1990-06-15 12:00 UT falls in a backward stretch of the wobble
+    jd := julianDay(time.Date(1990, 6, 15, 12, 0, 0, 0, time.UTC))
+    omega := 2 * math.Pi / wobblePeriod
+    if math.Cos(omega*(jd-2451545)) >= 0 {
+        t.Fatal("test date is not retrograde in the wobble")
+    }
+    out := postReading(t, `{"year": 1990, "month": 6, "day": 15, "hour": 12, "lat": 0, "lon": 0, "timezone": "UTC"}`)
+    if out["retrograde"] != true || out["speed"].(float64) >= 0 {
+        t.Errorf("retrograde %v, speed %v", out["retrograde"], out["speed"])
+    }
+    if _, ok := out["retrograde_interpretation"]; !ok {
+        t.Error("no retrograde interpretation")
+    }
+    for _, key := range []string{"previous_station", "next_station"} {
+        s, ok := out[key].(map[string]interface{})
+        if !ok {
+            t.Errorf("no %s", key)
+            continue
+        }
+        if s["date"] == "" || s["type"] == "" {
+            t.Errorf("%s incomplete: %v", key, s)
+        }
+    }
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "net/http"
+    "time"
+)
+
+// ===== Solar and lunar returns =====
+
+const returnAspectOrb = 5.0 // degrees, Chiron to the return chart's points
+
+type returnRequest struct {
+    BirthData
+    Type       string   `json:"type"`        // solar (default) or lunar
+    ReturnYear int      `json:"return_year"` // solar: the year of the birthday, default this year
+    After      string   `json:"after"`       // lunar: first return on or after this date (YYYY-MM-DD), default today
+    ReturnLat  *float64 `json:"return_lat"`  // where the return is spent; default the birth place
+    ReturnLon  *float64 `json:"return_lon"`
+}
+
+type ReturnAspect struct {
+    Point   string  `json:"point"`
+    Aspect  string  `json:"aspect"`
+    Orb     float64 `json:"orb"`
+    Meaning string  `json:"text"`
+}
+
+type ReturnChart struct {
+    Type      string          `json:"type"`
+    Exact     string          `json:"exact"` // UTC, RFC 3339
+    Lat       float64         `json:"lat"`
+    Lon       float64         `json:"lon"`
+    Ascendant ProgressedAngle `json:"ascendant"`
+    Sign      string          `json:"sign"`
+    Degree    float64         `json:"degree"`
+    House     int             `json:"house"`
+    Meaning   string          `json:"text"`
+    Aspects   []ReturnAspect  `json:"aspects"`
+    Zodiac    string          `json:"zodiac"`
+    Ayanamsa  string          `json:"ayanamsa,omitempty"`
+}
+
+// returnHouseTexts read Chiron's house in a return chart as the area of life
+// where the wound is active for the period the chart covers.
+var returnHouseTexts = map[int]string{
+    1:  "Chiron in the return 1st house brings the wound to the surface of the period: questions of identity, the body and self-assertion are tender, and showing up as yourself is the work.",
+    2:  "Chiron in the return 2nd house touches money, possessions and self-worth. Insecurity may flare; healing comes from valuing what you already are.",
+    3:  "Chiron in the return 3rd house highlights speech, learning and siblings or neighbours. Old fears of not being heard return so you can find your voice again.",
+    4:  "Chiron in the return 4th house turns attention home: family history, moves or the need for a safe base bring the wound close, and with it the chance to re-root.",
+    5:  "Chiron in the return 5th house stirs creativity, romance and children. Joy may feel risky; healing comes through play you allow yourself anyway.",
+    6:  "Chiron in the return 6th house points to health, routines and work. Small daily adjustments do more healing now than grand gestures.",
+    7:  "Chiron in the return 7th house plays out in partnership. Others mirror the wound; honest relating is both the trigger and the remedy.",
+    8:  "Chiron in the return 8th house goes deep: shared resources, intimacy and endings. What surfaces can be intense, and letting go is the medicine.",
+    9:  "Chiron in the return 9th house questions beliefs. Travel, study or a teacher challenge your worldview and help you author your own.",
+    10: "Chiron in the return 10th house makes the wound visible in career and reputation. Leading from your experience, not despite it, is the opportunity.",
+    11: "Chiron in the return 11th house concerns friends, groups and hopes. Feeling like an outsider may return; finding your people is part of the healing.",
+    12: "Chiron in the return 12th house works quietly. Rest, solitude and inner work bring old material up to be released, often before it is understood.",
+}
+
+// returnAspectTexts are formatted with the point Chiron aspects.
+var returnAspectTexts = map[string]string{
+    "conjunction": "Chiron conjunct %s fuses the wound with that part of the chart for the period; expect it to be felt directly.",
+    "sextile":     "Chiron sextile %s offers an easy opening: healing through %[1]s themes is available if you take it up.",
+    "square":      "Chiron square %s creates friction; %[1]s matters press on the wound and ask for change.",
+    "trine":       "Chiron trine %s lets healing flow through %[1]s themes with little resistance.",
+    "opposition":  "Chiron opposite %s projects the wound outward; others carry the %[1]s side of the lesson until you reclaim it.",
+}
+
+// findReturn refines guess with Newton's method until body is back at
+// longitude target.
+func findReturn(body int, target, guess float64, opts calcOptions) (float64, error) {
+    jd := guess
+    var err error
+    converged := false
+    withEphemeris(opts, func() {
+        for i := 0; i < 30; i++ {
+            var lon, speed float64
+            if lon, speed, err = bodySpeedAt(jd, body, opts.flags()); err != nil {
+                return
+            }
+            d := norm180(lon - target)
+            if math.Abs(d) < 1e-7 {
+                converged = true
+                return
+            }
+            jd -= d / speed
+        }
+    })
+    if err != nil {
+        ephemerisErrors.inc("bodies")
+        return 0, err
+    }
+    if !converged {
+        return 0, errors.New("return search did not converge")
+    }
+    return jd, nil
+}
+
+// findLunarReturn is the first moment on or after the JD after that the Moon
+// is back at longitude target. Newton's method from a mean-motion guess can
+// step onto the return a month either side, so the crossing is bracketed a
+// day at a time, which the Moon can never overshoot, and then bisected.
+func findLunarReturn(target, after float64, opts calcOptions) (float64, error) {
+    var err error
+    off := func(jd float64) float64 {
+        var lon float64
+        if err == nil {
+            lon, _, err = bodySpeedAt(jd, SE_MOON, opts.flags())
+        }
+        return norm180(lon - target)
+    }
+    lo, found := after, false
+    withEphemeris(opts, func() {
+        prev := off(lo)
+        for i := 0; i < 32 && err == nil; i++ {
+            hi := lo + 1
+            next := off(hi)
+            if prev < 0 && next >= 0 {
+                for hi-lo > 1e-9 && err == nil {
+                    if mid := (lo + hi) / 2; off(mid) < 0 {
+                        lo = mid
+                    } else {
+                        hi = mid
+                    }
+                }
+                lo, found = hi, true
+                return
+            }
+            lo, prev = hi, next
+        }
+    })
+    if err != nil {
+        ephemerisErrors.inc("bodies")
+        return 0, err
+    }
+    if !found {
+        return 0, errors.New("return search did not converge")
+    }
+    return lo, nil
+}
+
+func returnsHandler(w http.ResponseWriter, r *http.Request) {
+    var req returnRequest
+    if err := decodeJSON(w, r, &req); err != nil {
+        writeDecodeError(w, err)
+        return
+    }
+    if req.UnknownTime {
+        http.Error(w, "returns need a birth time", http.StatusBadRequest)
+        return
+    }
+    if req.Type == "" {
+        req.Type = "solar"
+    }
+    if req.Type != "solar" && req.Type != "lunar" {
+        http.Error(w, "type must be solar or lunar", http.StatusBadRequest)
+        return
+    }
+    if (req.ReturnLat == nil) != (req.ReturnLon == nil) {
+        http.Error(w, "return_lat and return_lon must be given together", http.StatusBadRequest)
+        return
+    }
+    utc, _, err := birthTime(req.BirthData)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    opts, err := resolveCalcOptions(req.BirthData)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    lat, lon := req.Lat, req.Lon
+    if req.ReturnLat != nil {
+        lat, lon = *req.ReturnLat, *req.ReturnLon
+    }
+
+    body := SE_SUN
+    var guess float64
+    if req.Type == "solar" {
+        year := req.ReturnYear
+        if year == 0 {
+            year = time.Now().Year()
+        }
+        if year < utc.Year() || year > utc.Year()+150 {
+            http.Error(w, "return_year out of range", http.StatusBadRequest)
+            return
+        }
+        guess = julianDay(utc.AddDate(year-utc.Year(), 0, 0))
+    } else {
+        body = SE_MOON
+        after := time.Now().UTC().Truncate(24 * time.Hour)
+        if req.After != "" {
+            if after, err = time.Parse("2006-01-02", req.After); err != nil {
+                http.Error(w, "after must be YYYY-MM-DD", http.StatusBadRequest)
+                return
+            }
+        }
+        guess = julianDay(after)
+    }
+
+    ctx := r.Context()
+    natalJD := julianDay(utc)
+    ephStart := time.Now()
+    natalLon, err := computeBodyLongitude(natalJD, body, opts)
+    var jd float64
+    if err == nil && body == SE_MOON {
+        jd, err = findLunarReturn(natalLon, guess, opts)
+    } else if err == nil {
+        jd, err = findReturn(body, natalLon, guess, opts)
+    }
+    var chiron float64
+    var points []chartPoint
+    if err == nil {
+        chiron, err = computeChironLongitude(jd, opts)
+    }
+    if err == nil {
+        points, err = computeChartPoints(jd, opts)
+    }
+    observeEphemeris(ctx, "returns", ephStart)
+    if err != nil {
+        http.Error(w, fmt.Sprintf("%s return calculation failed", req.Type), http.StatusInternalServerError)
+        return
+    }
+
+    chart, err := placeInHouses(ctx, jd, lat, lon, chiron, opts)
+    if err != nil {
+        http.Error(w, "house calculation failed", http.StatusInternalServerError)
+        return
+    }
+    points = append(points, chartPoint{"Ascendant", chart.Asc}, chartPoint{"Midheaven", chart.MC})
+
+    resp := ReturnChart{
+        Type:  req.Type,
+        Exact: jdToTime(jd).Format(time.RFC3339),
+        Lat:   lat,
+        Lon:   lon,
+        Ascendant: ProgressedAngle{
+            Longitude: round6(chart.Asc),
+            Sign:      signFromLongitude(chart.Asc),
+            Degree:    math.Round(math.Mod(chart.Asc, 30)*100) / 100,
+        },
+        Sign:     signFromLongitude(chiron),
+        Degree:   math.Round(math.Mod(chiron, 30)*100) / 100,
+        House:    chart.House,
+        Meaning:  returnHouseTexts[chart.House],
+        Aspects:  []ReturnAspect{},
+        Zodiac:   opts.Zodiac,
+        Ayanamsa: opts.Ayanamsa,
+    }
+    for _, p := range points {
+        if asp, orb, ok := findAspect(chiron, p.Lon, returnAspectOrb); ok {
+            resp.Aspects = append(resp.Aspects, ReturnAspect{
+                Point:   p.Name,
+                Aspect:  asp.Name,
+                Orb:     math.Round(orb*100) / 100,
+                Meaning: fmt.Sprintf(returnAspectTexts[asp.Name], p.Name),
+            })
+        }
+    }
+    writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "math"
+    "net/http"
+    "net/http/httptest"
+    "strconv"
+    "strings"
+    "testing"
+    "time"
+)
+
+// eccentricMoonEphemeris is the fake with an equation of centre added to
+// the Moon, exaggerated well past the real 6.3° so that Newton's method from
+// the mean-motion guess lands on the wrong return. The Moon still never
+// stops or turns back.
+type eccentricMoonEphemeris struct{ fakeEphemeris }
+
+const (
+    moonEccAmp     = 50.0      // degrees
+    moonAnomMotion = 13.064993 // degrees per day
+)
+
+func (m *eccentricMoonEphemeris) CalcUt(jd float64, body, flags int, xx []float64) error {
+    if err := m.fakeEphemeris.CalcUt(jd, body, flags, xx); err != nil || body != SE_MOON {
+        return err
+    }
+    anom := (134.963 + moonAnomMotion*(jd-2451545)) * deg2rad
+    xx[0] = normDeg(xx[0] + moonEccAmp*math.Sin(anom))
+    xx[3] += moonEccAmp * moonAnomMotion * deg2rad * math.Cos(anom)
+    return nil
+}
+
+// postReturn asks for a return and gives back its exact moment.
+func postReturn(t *testing.T, body string) time.Time {
+    t.Helper()
+    w := httptest.NewRecorder()
+    returnsHandler(w, httptest.NewRequest(http.MethodPost, "/api/returns", strings.NewReader(body)))
+    var chart ReturnChart
+    if err := json.Unmarshal(w.Body.Bytes(), &chart); w.Code != http.StatusOK || err != nil {
+        t.Fatalf("status %d %q", w.Code, strings.TrimSpace(w.Body.String()))
+    }
+    exact, err := time.Parse(time.RFC3339, chart.Exact)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return exact
+}
+
+// firstCrossing scans forward from jd for the moment body is next at
+// longitude target, independently of the Newton search under test.
+func firstCrossing(t *testing.T, body int, target, jd float64) float64 {
+    t.Helper()
+    xx := make([]float64, 6)
+    off := func(jd float64) float64 {
+        if err := eph.CalcUt(jd, body, 0, xx); err != nil {
+            t.Fatal(err)
+        }
+        return norm180(xx[0] - target)
+    }
+    for a := jd; a < jd+400; a += 0.05 {
+        b := a + 0.05
+        if off(a) >= 0 || off(b) < 0 {
+            continue
+        }
+        for b-a > 1e-7 {
+            if m := (a + b) / 2; off(m) < 0 {
+                a = m
+            } else {
+                b = m
+            }
+        }
+        return b
+    }
+    t.Fatalf("no crossing of %.4f in the 400 days from %.2f", target, jd)
+    return 0
+}
+
+const returnsBirth = `"year": 1990, "month": 6, "day": 15, "hour": 14.5, "timezone": "Europe/London"`
+
+func TestSolarReturnKnownDates(t *testing.T) {
+    useFake(t)
+    natalJD := julianDay(time.Date(1990, 6, 15, 13, 30, 0, 0, time.UTC))
+    year := 360 / fakeOrbits[SE_SUN][1]
+    for _, c := range []struct {
+        year  int
+        turns int
+    }{
+        {1991, 1},
+        {2000, 10},
+        {2024, 34},
+        {2090, 100},
+    } {
+        got := postReturn(t, `{`+returnsBirth+`, "return_year": `+strconv.Itoa(c.year)+`}`)
+        want := jdToTime(natalJD + float64(c.turns)*year)
+        if d := got.Sub(want); d < -time.Second || d > time.Second {
+            t.Errorf("%d: solar return %s, want %s", c.year, got, want)
+        }
+    }
+}
+
+func TestLunarReturnKnownDates(t *testing.T) {
+    useFake(t)
+    natalJD := julianDay(time.Date(1990, 6, 15, 13, 30, 0, 0, time.UTC))
+    month := 360 / fakeOrbits[SE_MOON][1]
+    for _, after := range []string{"1990-06-15", "1990-06-16", "2000-01-01", "2024-02-29", "2024-03-01"} {
+        from, _ := time.Parse("2006-01-02", after)
+        turns := math.Ceil((julianDay(from) - natalJD) / month)
+        want := jdToTime(natalJD + turns*month)
+        got := postReturn(t, `{`+returnsBirth+`, "type": "lunar", "after": "`+after+`"}`)
+        if d := got.Sub(want); d < -time.Second || d > time.Second {
+            t.Errorf("after %s: lunar return %s, want %s", after, got, want)
+        }
+    }
+}
+
+func TestLunarReturnIsFirstOnOrAfter(t *testing.T) {
+    useEphemeris(t, &eccentricMoonEphemeris{})
+    xx := make([]float64, 6)
+    for hours := 0; hours < 28*24; hours += 31 {
+        birth := time.Date(1990, 6, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(hours) * time.Hour)
+        if err := eph.CalcUt(julianDay(birth), SE_MOON, 0, xx); err != nil {
+            t.Fatal(err)
+        }
+        natalLon := xx[0]
+        for day := 0; day < 60; day += 3 {
+            after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, day)
+            got := postReturn(t, fmt.Sprintf(`{"year": 1990, "month": 6, "day": %d, "hour": %d, "timezone": "UTC", "type": "lunar", "after": %q}`,
+                birth.Day(), birth.Hour(), after.Format("2006-01-02")))
+            want := jdToTime(firstCrossing(t, SE_MOON, natalLon, julianDay(after)))
+            if d := got.Sub(want); d < -2*time.Second || d > 2*time.Second {
+                t.Errorf("born %s, after %s: lunar return %s, want %s", birth.Format("01-02 15h"), after.Format("2006-01-02"), got, want)
+            }
+        }
+    }
+}
@@ -0,0 +1,92 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+    "errors"
+    "flag"
+    "fmt"
+    "log/slog"
+    "net/http"
+    "os"
+)
+
+// ===== Server entry point =====
+
+func main() {
+    if len(os.Args) > 1 && os.Args[1] == "config" {
+        os.Exit(configCommand(os.Args[2:]))
+    }
+
+    cfg, err := loadConfig(os.Args[1:])
+    if err != nil {
+        if errors.Is(err, flag.ErrHelp) {
+            return
+        }
+        fmt.Fprintln(os.Stderr, "invalid configuration:", err)
+        os.Exit(2)
+    }
+    appConfig = cfg
+    setupLogging(cfg.Log)
+
+    if eph, err = openEphemeris(cfg.Ephemeris); err != nil {
+        slog.Error("opening ephemeris", "backend", cfg.Ephemeris.Backend, "err", err)
+        os.Exit(1)
+    }
+    if err := checkEphemerisDefaults(eph, cfg.Ephemeris); err != nil {
+        slog.Error("ephemeris backend can't serve the configured defaults", "backend", eph.Name(), "err", err)
+        os.Exit(1)
+    }
+    if cfg.Ephemeris.Backend == "fake" || cfg.Ephemeris.Compare != "" {
+        // The table would answer Chiron ahead of the backend being exercised
+        appConfig.Ephemeris.ChironTable = false
+    }
+    if chironTableErr != nil && cfg.Ephemeris.ChironTable {
+        slog.Warn("embedded Chiron table unusable, answering Chiron from the backend", "backend", eph.Name(), "err", chironTableErr)
+    }
+    if cfg.Interpretations.Path != "" {
+        if err := loadInterpretationFile(cfg.Interpretations.Path); err != nil {
+            slog.Error("loading interpretations", "path", cfg.Interpretations.Path, "err", err)
+            os.Exit(1)
+        }
+    }
+    if cfg.Interpretations.SabianPath != "" {
+        if err := loadSabianFile(cfg.Interpretations.SabianPath); err != nil {
+            slog.Error("loading Sabian symbols", "path", cfg.Interpretations.SabianPath, "err", err)
+            os.Exit(1)
+        }
+    }
+
+    go warmCohorts()
+
+    // Root route serves HTML frontend
+    http.HandleFunc("/", homeHandler)
+
+    // API routes
+    http.HandleFunc("/api/health", healthHandler)
+    http.HandleFunc("/livez", livezHandler)
+    http.HandleFunc("/readyz", readyzHandler)
+    http.HandleFunc("/api/chiron", chironHandler)
+    http.HandleFunc("/api/ayanamsas", ayanamsasHandler)
+    http.HandleFunc("/api/astrocartography", astrocartographyHandler)
+    http.HandleFunc("/api/astrocartography/nearest", nearestLineHandler)
+    http.HandleFunc("/api/progressions", progressionsHandler)
+    http.HandleFunc("/api/returns", returnsHandler)
+    http.HandleFunc("/api/relationship", relationshipHandler)
+    http.HandleFunc("/api/cohorts", cohortsHandler)
+    http.HandleFunc("/api/ephemeris", ephemerisTableHandler)
+    http.HandleFunc("/metrics", metricsHandler)
+
+    slog.Info("🚀 Chiron Oracle starting", "addr", cfg.Server.addr(), "tls", cfg.TLS.CertFile != "",
+        "house_system", cfg.Ephemeris.HouseSystem, "zodiac", cfg.Ephemeris.Zodiac,
+        "version", version, "commit", commit)
+
+    handler := rateLimitMiddleware(cfg.RateLimit, http.DefaultServeMux)
+    handler = corsMiddleware(cfg.CORS, handler)
+    handler = instrumentHandler(http.DefaultServeMux, handler)
+    handler = requestLogger(http.DefaultServeMux, handler)
+    if err := runServer(cfg.Server, cfg.TLS, handler); err != nil {
+        slog.Error("server stopped", "err", err)
+        os.Exit(1)
+    }
+}
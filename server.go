@@ -0,0 +1,157 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "log/slog"
+    "net"
+    "net/http"
+    "os/signal"
+    "strconv"
+    "sync/atomic"
+    "syscall"
+    "time"
+)
+
+// ===== Server lifecycle =====
+
+type serverConfig struct {
+    BindAddress       string        `yaml:"bind_address"`
+    Port              int           `yaml:"port"`
+    ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+    ReadTimeout       time.Duration `yaml:"read_timeout"`
+    WriteTimeout      time.Duration `yaml:"write_timeout"`
+    IdleTimeout       time.Duration `yaml:"idle_timeout"`
+    ShutdownDelay     time.Duration `yaml:"shutdown_delay"` // how long /readyz fails before listeners close
+    ShutdownGrace     time.Duration `yaml:"shutdown_grace"` // how long in-flight requests get to finish
+    MaxBodyBytes      int64         `yaml:"max_body_bytes"`
+}
+
+func (c serverConfig) addr() string {
+    return net.JoinHostPort(c.BindAddress, strconv.Itoa(c.Port))
+}
+
+func defaultServerConfig() serverConfig {
+    return serverConfig{
+        Port:              8080,
+        ReadHeaderTimeout: 5 * time.Second,
+        ReadTimeout:       15 * time.Second,
+        WriteTimeout:      60 * time.Second,
+        IdleTimeout:       120 * time.Second,
+        ShutdownDelay:     5 * time.Second,
+        ShutdownGrace:     20 * time.Second,
+        MaxBodyBytes:      1 << 20,
+    }
+}
+
+// maxBodyBytes caps request bodies read by decodeJSON.
+var maxBodyBytes int64 = defaultServerConfig().MaxBodyBytes
+
+// decodeJSON decodes a request body, refusing anything over maxBodyBytes.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+    r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+    return json.NewDecoder(r.Body).Decode(v)
+}
+
+// writeDecodeError answers a failed decodeJSON: 413 for oversized bodies,
+// 400 for everything else.
+func writeDecodeError(w http.ResponseWriter, err error) {
+    var tooLarge *http.MaxBytesError
+    if errors.As(err, &tooLarge) {
+        http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+        return
+    }
+    http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+// shuttingDown flips once SIGTERM/SIGINT arrives so /readyz stops
+// advertising the pod while in-flight requests drain.
+var shuttingDown atomic.Bool
+
+func checkNotShuttingDown(ctx context.Context) error {
+    if shuttingDown.Load() {
+        return errors.New("server is shutting down")
+    }
+    return nil
+}
+
+// runServer serves until SIGINT or SIGTERM, keeps serving for
+// cfg.ShutdownDelay while /readyz reports the shutdown, then drains
+// connections for up to cfg.ShutdownGrace and releases the ephemeris files.
+func runServer(cfg serverConfig, tlsCfg tlsConfig, handler http.Handler) error {
+    maxBodyBytes = cfg.MaxBodyBytes
+
+    srv := &http.Server{
+        Addr:              cfg.addr(),
+        Handler:           handler,
+        ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+        ReadTimeout:       cfg.ReadTimeout,
+        WriteTimeout:      cfg.WriteTimeout,
+        IdleTimeout:       cfg.IdleTimeout,
+        ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+    }
+    servers := []*http.Server{srv}
+
+    ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+    defer stop()
+
+    if tlsCfg.enabled() {
+        reloader, err := newCertReloader(tlsCfg)
+        if err != nil {
+            return err
+        }
+        srv.TLSConfig = reloader.tlsConfig()
+        go reloader.watch(ctx)
+
+        if tlsCfg.RedirectHTTPPort != 0 {
+            servers = append(servers, newRedirectServer(cfg, tlsCfg.RedirectHTTPPort))
+        }
+    }
+
+    errc := make(chan error, len(servers))
+    for _, s := range servers {
+        go func(s *http.Server) {
+            if s.TLSConfig != nil {
+                // Certificates come from the reloader, not from file arguments
+                errc <- s.ListenAndServeTLS("", "")
+                return
+            }
+            errc <- s.ListenAndServe()
+        }(s)
+    }
+
+    select {
+    case err := <-errc:
+        for _, s := range servers {
+            s.Close()
+        }
+        return err
+    case <-ctx.Done():
+    }
+
+    stop() // a second signal now exits at once
+    shuttingDown.Store(true)
+    // Load balancers only stop sending traffic once they have polled /readyz
+    slog.Info("shutdown signal received, failing readiness", "delay", cfg.ShutdownDelay.String())
+    time.Sleep(cfg.ShutdownDelay)
+    slog.Info("draining connections", "grace_period", cfg.ShutdownGrace.String())
+
+    shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownGrace)
+    defer cancel()
+    var errs []error
+    for _, s := range servers {
+        if err := s.Shutdown(shutdownCtx); err != nil {
+            s.Close()
+            errs = append(errs, err)
+        }
+    }
+    if err := errors.Join(errs...); err != nil {
+        // Handlers may still be inside Swiss Ephemeris, so leave its files open
+        return err
+    }
+
+    eph.Close()
+    slog.Info("server stopped cleanly")
+    return nil
+}
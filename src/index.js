@@ -1,56 +1,118 @@
+// Cloudflare Worker: serves the frontend from ./public and forwards /api/* to
+// the Go server at API_BASE, caching deterministic answers at the edge.
+
+// Routes whose answer depends only on the request. /api/chiron is keyed on
+// its JSON body; the GET routes on their query string.
+const CACHEABLE = {
+  "/api/chiron": "POST",
+  "/api/cohorts": "GET",
+  "/api/ayanamsas": "GET",
+};
+
+const DEFAULT_CACHE_TTL = 86400; // seconds
+
 export default {
-  async fetch(request) {
-    const html = `
-      <!DOCTYPE html>
-      <html lang="en">
-      <head>
-        <meta charset="UTF-8">
-        <title>Chiron Oracle ✨</title>
-        <style>
-          body {
-            font-family: 'Segoe UI', sans-serif;
-            background: linear-gradient(135deg, #fdf6e3, #ffe4e1);
-            color: #333;
-            padding: 2rem;
-            max-width: 800px;
-            margin: auto;
-          }
-          h1 {
-            color: #b22222;
-            text-align: center;
-            font-size: 2.5rem;
-          }
-          p {
-            line-height: 1.6;
-            font-size: 1.2rem;
-          }
-          .oracle-box {
-            background: #fff;
-            border-radius: 12px;
-            padding: 1.5rem;
-            box-shadow: 0 4px 12px rgba(0,0,0,0.1);
-            margin-top: 2rem;
-          }
-          footer {
-            margin-top: 2rem;
-            font-size: 0.9em;
-            text-align: center;
-            color: #777;
-          }
-        </style>
-      </head>
-      <body>
-        <h1>✨ Chiron Oracle ✨</h1>
-        <div class="oracle-box">
-          <p>Welcome, seeker. Your wounds and strengths will be revealed soon...</p>
-          <p>This is your oracle’s first heartbeat on the Cloudflare edge.</p>
-        </div>
-        <footer>Powered by Cloudflare Workers · Built by Rayana</footer>
-      </body>
-      </html>
-    `;
-    return new Response(html, {
-      headers: { "content-type": "text/html" },
-    });
+  async fetch(request, env, ctx) {
+    const url = new URL(request.url);
+    if (!url.pathname.startsWith("/api/")) {
+      return env.ASSETS.fetch(request);
+    }
+    if (!env.API_BASE) {
+      return new Response("API_BASE is not configured", { status: 503 });
+    }
+    if (CACHEABLE[url.pathname] === request.method) {
+      return cached(request, url, env, ctx);
+    }
+    return forward(request, url, env);
   },
 };
+
+// forward sends the request on to the Go server unchanged apart from the host.
+async function forward(request, url, env, body) {
+  const target = new URL(url.pathname + url.search, env.API_BASE);
+  const headers = new Headers(request.headers);
+  headers.delete("host");
+  const client = request.headers.get("CF-Connecting-IP");
+  if (client) {
+    headers.set("X-Forwarded-For", client);
+  }
+  try {
+    return await fetch(target, {
+      method: request.method,
+      headers,
+      body: body !== undefined ? body : request.body,
+      redirect: "manual",
+    });
+  } catch (err) {
+    return new Response("upstream unavailable", { status: 502 });
+  }
+}
+
+async function cached(request, url, env, ctx) {
+  let body;
+  let key = url.pathname + url.search;
+  if (request.method === "POST") {
+    body = await request.text();
+    let parsed;
+    try {
+      parsed = JSON.parse(body);
+    } catch {
+      // Let the Go server word the 400
+      return forward(request, url, env, body);
+    }
+    key += "/" + (await sha256(canonicalJSON(parsed)));
+  }
+  // The Cache API only stores GETs, so POST bodies become part of a URL
+  const cacheURL = new URL("/__cache" + key, url.origin);
+  // CORS headers vary by Origin, which the Cache API won't key on by itself
+  const origin = request.headers.get("Origin");
+  if (origin) {
+    cacheURL.searchParams.set("__origin", origin);
+  }
+  const cacheKey = new Request(cacheURL, { method: "GET" });
+  const cache = caches.default;
+
+  const hit = await cache.match(cacheKey);
+  if (hit) {
+    return withCacheStatus(hit, "HIT");
+  }
+
+  const response = await forward(request, url, env, body);
+  if (response.status !== 200) {
+    return response;
+  }
+  const ttl = Number(env.CACHE_TTL) || DEFAULT_CACHE_TTL;
+  const stored = new Response(response.body, response);
+  const entry = stored.clone();
+  entry.headers.set("Cache-Control", `public, max-age=${ttl}`);
+  // These belong to the request that filled the cache, not later ones
+  entry.headers.delete("X-Request-ID");
+  entry.headers.delete("traceparent");
+  ctx.waitUntil(cache.put(cacheKey, entry));
+  return withCacheStatus(stored, "MISS");
+}
+
+function withCacheStatus(response, status) {
+  const out = new Response(response.body, response);
+  out.headers.set("X-Cache", status);
+  out.headers.delete("Cache-Control");
+  return out;
+}
+
+// canonicalJSON sorts object keys so equivalent bodies share a cache entry.
+function canonicalJSON(value) {
+  if (Array.isArray(value)) {
+    return "[" + value.map(canonicalJSON).join(",") + "]";
+  }
+  if (value && typeof value === "object") {
+    return "{" + Object.keys(value).sort()
+      .map((k) => JSON.stringify(k) + ":" + canonicalJSON(value[k]))
+      .join(",") + "}";
+  }
+  return JSON.stringify(value);
+}
+
+async function sha256(text) {
+  const digest = await crypto.subtle.digest("SHA-256", new TextEncoder().encode(text));
+  return [...new Uint8Array(digest)].map((b) => b.toString(16).padStart(2, "0")).join("");
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+    "context"
+    "crypto/tls"
+    "crypto/x509"
+    "errors"
+    "fmt"
+    "log/slog"
+    "net"
+    "net/http"
+    "os"
+    "strconv"
+    "sync"
+    "time"
+)
+
+// ===== TLS =====
+
+type tlsConfig struct {
+    CertFile         string        `yaml:"cert_file"`
+    KeyFile          string        `yaml:"key_file"`
+    ClientCAFile     string        `yaml:"client_ca_file"`     // CA bundle for partner client certificates
+    ClientAuth       string        `yaml:"client_auth"`        // none, optional or require
+    MinVersion       string        `yaml:"min_version"`        // 1.2 or 1.3
+    ReloadInterval   time.Duration `yaml:"reload_interval"`    // how often cert files are checked for changes
+    RedirectHTTPPort int           `yaml:"redirect_http_port"` // plain HTTP port redirecting to HTTPS; 0 disables
+}
+
+func defaultTLSConfig() tlsConfig {
+    return tlsConfig{
+        ClientAuth:     "none",
+        MinVersion:     "1.2",
+        ReloadInterval: 10 * time.Second,
+    }
+}
+
+func (c tlsConfig) enabled() bool {
+    return c.CertFile != ""
+}
+
+var clientAuthModes = map[string]tls.ClientAuthType{
+    "none":     tls.NoClientCert,
+    "optional": tls.VerifyClientCertIfGiven, // browsers connect as usual, partners present a cert
+    "require":  tls.RequireAndVerifyClientCert,
+}
+
+var tlsVersions = map[string]uint16{
+    "1.2": tls.VersionTLS12,
+    "1.3": tls.VersionTLS13,
+}
+
+func (c tlsConfig) validate() []error {
+    var errs []error
+    if (c.CertFile == "") != (c.KeyFile == "") {
+        errs = append(errs, errors.New("tls.cert_file and tls.key_file must be set together"))
+    }
+    if _, ok := clientAuthModes[c.ClientAuth]; !ok {
+        errs = append(errs, fmt.Errorf("tls.client_auth %q unknown (none, optional or require)", c.ClientAuth))
+    }
+    if c.ClientAuth != "none" && c.ClientCAFile == "" {
+        errs = append(errs, errors.New("tls.client_ca_file is required when tls.client_auth is enabled"))
+    }
+    if _, ok := tlsVersions[c.MinVersion]; !ok {
+        errs = append(errs, fmt.Errorf("tls.min_version %q unknown (1.2 or 1.3)", c.MinVersion))
+    }
+    if c.ReloadInterval <= 0 {
+        errs = append(errs, errors.New("tls.reload_interval must be positive"))
+    }
+    if c.RedirectHTTPPort < 0 || c.RedirectHTTPPort > 65535 {
+        errs = append(errs, fmt.Errorf("tls.redirect_http_port %d out of range", c.RedirectHTTPPort))
+    }
+    if c.RedirectHTTPPort != 0 && !c.enabled() {
+        errs = append(errs, errors.New("tls.redirect_http_port needs tls.cert_file"))
+    }
+    if !c.enabled() && (c.ClientCAFile != "" || c.ClientAuth != "none") {
+        errs = append(errs, errors.New("tls.client_ca_file and tls.client_auth need tls.cert_file"))
+    }
+    for _, f := range []string{c.CertFile, c.KeyFile, c.ClientCAFile} {
+        if f != "" {
+            if _, err := os.Stat(f); err != nil {
+                errs = append(errs, err)
+            }
+        }
+    }
+    return errs
+}
+
+// certReloader serves the current certificate and client CA pool, and
+// reloads them when the files change on disk so renewals need no restart.
+type certReloader struct {
+    cfg  tlsConfig
+    base *tls.Config
+
+    mu       sync.RWMutex
+    cert     *tls.Certificate
+    clientCA *x509.CertPool
+    modTimes map[string]time.Time
+}
+
+func newCertReloader(cfg tlsConfig) (*certReloader, error) {
+    r := &certReloader{
+        cfg: cfg,
+        base: &tls.Config{
+            MinVersion: tlsVersions[cfg.MinVersion],
+            ClientAuth: clientAuthModes[cfg.ClientAuth],
+            NextProtos: []string{"h2", "http/1.1"},
+        },
+    }
+    if err := r.reload(); err != nil {
+        return nil, err
+    }
+    return r, nil
+}
+
+func (r *certReloader) files() []string {
+    files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+    if r.cfg.ClientCAFile != "" {
+        files = append(files, r.cfg.ClientCAFile)
+    }
+    return files
+}
+
+func (r *certReloader) currentModTimes() (map[string]time.Time, error) {
+    times := map[string]time.Time{}
+    for _, f := range r.files() {
+        fi, err := os.Stat(f)
+        if err != nil {
+            return nil, err
+        }
+        times[f] = fi.ModTime()
+    }
+    return times, nil
+}
+
+// reload reads the key pair and client CA bundle. On failure the previous
+// certificate stays in use.
+func (r *certReloader) reload() error {
+    times, err := r.currentModTimes()
+    if err != nil {
+        return err
+    }
+    cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+    if err != nil {
+        return err
+    }
+    var pool *x509.CertPool
+    if r.cfg.ClientCAFile != "" {
+        pem, err := os.ReadFile(r.cfg.ClientCAFile)
+        if err != nil {
+            return err
+        }
+        pool = x509.NewCertPool()
+        if !pool.AppendCertsFromPEM(pem) {
+            return fmt.Errorf("%s: no certificates found", r.cfg.ClientCAFile)
+        }
+    }
+
+    r.mu.Lock()
+    r.cert, r.clientCA, r.modTimes = &cert, pool, times
+    r.mu.Unlock()
+    return nil
+}
+
+func (r *certReloader) changed() bool {
+    times, err := r.currentModTimes()
+    if err != nil {
+        // Mid-rotation a file can briefly be missing; try again next tick
+        return false
+    }
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    for f, t := range times {
+        if !t.Equal(r.modTimes[f]) {
+            return true
+        }
+    }
+    return false
+}
+
+// watch polls the certificate files until ctx is cancelled.
+func (r *certReloader) watch(ctx context.Context) {
+    ticker := time.NewTicker(r.cfg.ReloadInterval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+            if !r.changed() {
+                continue
+            }
+            if err := r.reload(); err != nil {
+                slog.Error("TLS reload failed, keeping previous certificate", "err", err)
+                continue
+            }
+            slog.Info("TLS certificate reloaded", "cert_file", r.cfg.CertFile)
+        }
+    }
+}
+
+// tlsConfig hands each handshake the most recently loaded certificate and CA pool.
+func (r *certReloader) tlsConfig() *tls.Config {
+    cfg := r.base.Clone()
+    cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+        r.mu.RLock()
+        defer r.mu.RUnlock()
+        c := r.base.Clone()
+        c.Certificates = []tls.Certificate{*r.cert}
+        c.ClientCAs = r.clientCA
+        return c, nil
+    }
+    return cfg
+}
+
+// newRedirectServer answers plain HTTP on port with a permanent redirect to
+// the HTTPS listener.
+func newRedirectServer(srv serverConfig, port int) *http.Server {
+    return &http.Server{
+        Addr:              net.JoinHostPort(srv.BindAddress, strconv.Itoa(port)),
+        ReadHeaderTimeout: srv.ReadHeaderTimeout,
+        IdleTimeout:       srv.IdleTimeout,
+        Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            host := r.Host
+            if h, _, err := net.SplitHostPort(host); err == nil {
+                host = h
+            }
+            if srv.Port != 443 {
+                host = net.JoinHostPort(host, strconv.Itoa(srv.Port))
+            }
+            http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+        }),
+    }
+}
+
+// clientCertSubject returns the verified client certificate's common name,
+// if the caller authenticated with one.
+func clientCertSubject(r *http.Request) string {
+    if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+        return ""
+    }
+    return r.TLS.VerifiedChains[0][0].Subject.CommonName
+}
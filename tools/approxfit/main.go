@@ -0,0 +1,581 @@
+//go:build cgo
+
+// Command approxfit fits the data tables of the pure-Go ephemeris against
+// Swiss Ephemeris and writes them as Go source (approx_tables.go): JPL's
+// Keplerian elements with fitted periodic corrections for each planet, and a
+// quadratic for each ayanamsa. It prints the worst residual of every fit.
+//
+//	go run ./tools/approxfit -ephe ./swisseph/ephe -o approx_tables.go
+package main
+
+import (
+    "bytes"
+    "flag"
+    "fmt"
+    "math"
+    "os"
+    "runtime"
+    "sort"
+    "time"
+
+    swe "github.com/mshafiee/swephgo"
+)
+
+const (
+    seEarth      = 14
+    seflgSwieph  = 2
+    seflgHelctr  = 8
+    seflgTruepos = 16
+    seflgJ2000   = 32
+    seflgNonut   = 64
+    seflgNogdefl = 512
+    seflgNoaberr = 1024
+    seEclNut     = -1
+
+    earthBary = -100 // key of the Earth-Moon barycentre, as in the server
+    moonRatio = 81.30057
+    deg2rad   = math.Pi / 180
+)
+
+// Corrections are Chebyshev series in time plus periodic terms whose
+// amplitudes vary as Chebyshev polynomials of degree modDegree. Longitude and
+// latitude are fitted in arcseconds, distance in micro-AU.
+const (
+    polyCoef  = 9
+    modDegree = 2
+    maxTerms  = 16
+)
+
+// targets are the residuals, in arcseconds as seen from the Earth, at which a
+// planet needs no more terms: longitude, latitude, distance.
+var targets = [3]float64{10, 5, 10}
+
+// nearest is each body's least distance from the Earth, AU, which turns a
+// distance error into the largest angle it can cause.
+var nearest = map[int]float64{2: 0.55, 3: 0.26, earthBary: 1, 4: 0.37, 5: 3.9, 6: 8, 7: 17, 8: 29, 9: 28}
+
+// elements are JPL's approximate Keplerian elements (Standish, valid
+// 1800-2050): a (AU), e, I, L, long. perihelion, long. node (degrees), then
+// the rate of each per century.
+var elements = []struct {
+    name string
+    body int
+    el   [12]float64
+}{
+    {"SE_MERCURY", 2, [12]float64{0.38709927, 0.20563593, 7.00497902, 252.25032350, 77.45779628, 48.33076593,
+        0.00000037, 0.00001906, -0.00594749, 149472.67411175, 0.16047689, -0.12534081}},
+    {"SE_VENUS", 3, [12]float64{0.72333566, 0.00677672, 3.39467605, 181.97909950, 131.60246718, 76.67984255,
+        0.00000390, -0.00004107, -0.00078890, 58517.81538729, 0.00268329, -0.27769418}},
+    {"earthBary", earthBary, [12]float64{1.00000261, 0.01671123, -0.00001531, 100.46457166, 102.93768193, 0.0,
+        0.00000562, -0.00004392, -0.01294668, 35999.37244981, 0.32327364, 0.0}},
+    {"SE_MARS", 4, [12]float64{1.52371034, 0.09339410, 1.84969142, -4.55343205, -23.94362959, 49.55953891,
+        0.00001847, 0.00007882, -0.00813131, 19140.30268499, 0.44441088, -0.29257343}},
+    {"SE_JUPITER", 5, [12]float64{5.20288700, 0.04838624, 1.30439695, 34.39644051, 14.72847983, 100.47390909,
+        -0.00011607, -0.00013253, -0.00183714, 3034.74612775, 0.21252668, 0.20469106}},
+    {"SE_SATURN", 6, [12]float64{9.53667594, 0.05386179, 2.48599187, 49.95424423, 92.59887831, 113.66242448,
+        -0.00125060, -0.00050991, 0.00193609, 1222.49362201, -0.41897216, -0.28867794}},
+    {"SE_URANUS", 7, [12]float64{19.18916464, 0.04725744, 0.77263783, 313.23810451, 170.95427630, 74.01692503,
+        -0.00196176, -0.00004397, -0.00242939, 428.48202785, 0.40805281, 0.04240589}},
+    {"SE_NEPTUNE", 8, [12]float64{30.06992276, 0.00859048, 1.77004347, -55.12002969, 44.96476227, 131.78422574,
+        0.00026291, 0.00005105, 0.00035372, 218.45945325, -0.32241464, -0.00508664}},
+    {"SE_PLUTO", 9, [12]float64{39.48211675, 0.24882730, 17.14001206, 238.92903833, 224.06891629, 110.30393684,
+        -0.00031596, 0.00005170, 0.00004818, 145.20780515, -0.04062942, -0.01183482}},
+}
+
+// perturbers are the bodies whose mean anomalies enter each planet's terms.
+var perturbers = map[int][]int{
+    2:         {3, earthBary, 5},
+    3:         {2, earthBary, 5},
+    earthBary: {3, 4, 5, 6},
+    4:         {earthBary, 5, 3},
+    5:         {6, 7},
+    6:         {5, 7},
+    7:         {5, 6, 8},
+    8:         {5, 6, 7},
+    9:         {5, 6, 7, 8},
+}
+
+var elementsOf = map[int][12]float64{}
+
+// fitStart and fitEnd bound the fit, in centuries from J2000.
+var fitStart, fitEnd float64
+
+func centuries(jd float64) float64 { return (jd - 2451545) / 36525 }
+
+func julianDay(t time.Time) float64 {
+    return float64(t.Unix())/86400 + 2440587.5
+}
+
+func normDeg(x float64) float64 {
+    x = math.Mod(x, 360)
+    if x < 0 {
+        x += 360
+    }
+    return x
+}
+
+// kepler mirrors heliocentric in backend_approx.go without the corrections:
+// ecliptic longitude, latitude and distance, J2000, at TT centuries t.
+func kepler(body int, t float64) (lon, lat, dist float64) {
+    el := elementsOf[body]
+    a := el[0] + el[6]*t
+    e := el[1] + el[7]*t
+    i := (el[2] + el[8]*t) * deg2rad
+    l := el[3] + el[9]*t
+    peri := el[4] + el[10]*t
+    node := el[5] + el[11]*t
+    w := (peri - node) * deg2rad
+    m := normDeg(l-peri) * deg2rad
+    ea := m + e*math.Sin(m)
+    for k := 0; k < 10; k++ {
+        ea -= (ea - e*math.Sin(ea) - m) / (1 - e*math.Cos(ea))
+    }
+    xp := a * (math.Cos(ea) - e)
+    yp := a * math.Sqrt(1-e*e) * math.Sin(ea)
+    n := node * deg2rad
+    cw, sw, cn, sn, ci, si := math.Cos(w), math.Sin(w), math.Cos(n), math.Sin(n), math.Cos(i), math.Sin(i)
+    x := (cw*cn-sw*sn*ci)*xp + (-sw*cn-cw*sn*ci)*yp
+    y := (cw*sn+sw*cn*ci)*xp + (-sw*sn+cw*cn*ci)*yp
+    z := sw*si*xp + cw*si*yp
+    return spherical(x, y, z)
+}
+
+func spherical(x, y, z float64) (lon, lat, dist float64) {
+    return normDeg(math.Atan2(y, x) / deg2rad), math.Atan2(z, math.Hypot(x, y)) / deg2rad, math.Sqrt(x*x + y*y + z*z)
+}
+
+func meanAnomaly(body int, t float64) float64 {
+    el := elementsOf[body]
+    return (el[3] + el[9]*t - el[4] - el[10]*t) * deg2rad
+}
+
+// motion is a body's mean motion in anomaly, degrees per century.
+func motion(body int) float64 {
+    el := elementsOf[body]
+    return el[9] - el[10]
+}
+
+func calc(jd float64, body, flags int) []float64 {
+    xx := make([]float64, 6)
+    serr := make([]byte, 256)
+    if swe.Calc(jd, body, flags, xx, serr) < 0 {
+        fmt.Fprintf(os.Stderr, "approxfit: %s\n", bytes.TrimRight(serr, "\x00"))
+        os.Exit(1)
+    }
+    return xx
+}
+
+// geometric returns Swiss Ephemeris's geometric heliocentric J2000 position;
+// for the barycentre, the Earth plus the Moon's share.
+func geometric(body int, jd float64) (lon, lat, dist float64) {
+    const flags = seflgSwieph | seflgHelctr | seflgJ2000 | seflgNonut | seflgTruepos | seflgNogdefl | seflgNoaberr
+    if body != earthBary {
+        xx := calc(jd, body, flags)
+        return xx[0], xx[1], xx[2]
+    }
+    rect := func(xx []float64) [3]float64 {
+        l, b := xx[0]*deg2rad, xx[1]*deg2rad
+        return [3]float64{xx[2] * math.Cos(b) * math.Cos(l), xx[2] * math.Cos(b) * math.Sin(l), xx[2] * math.Sin(b)}
+    }
+    e := rect(calc(jd, seEarth, flags))
+    m := rect(calc(jd, 1, flags&^seflgHelctr))
+    k := 1 / (1 + moonRatio)
+    return spherical(e[0]+k*m[0], e[1]+k*m[1], e[2]+k*m[2])
+}
+
+// term is a periodic argument: self times the planet's mean anomaly plus
+// mult times the perturber's.
+type term struct{ self, body, mult int }
+
+func (a term) arg(planet int, t float64) float64 {
+    v := float64(a.self) * meanAnomaly(planet, t)
+    if a.body != 0 {
+        v += float64(a.mult) * meanAnomaly(a.body, t)
+    }
+    return v
+}
+
+// candidates lists the arguments worth trying for a planet: harmonics of its
+// own anomaly, then combinations with each perturber in order of increasing
+// multiples. Arguments slower than the polynomial can follow, or too close
+// in frequency to one already listed to be told apart over four centuries
+// once their amplitudes drift, are left out.
+func candidates(planet int) []term {
+    var all []term
+    for k := 1; k <= 3; k++ {
+        all = append(all, term{k, 0, 0})
+    }
+    var mixed []term
+    for _, q := range perturbers[planet] {
+        kmax := 3
+        if (planet == 5 || planet == 6) && (q == 5 || q == 6) {
+            kmax = 5 // the Jupiter-Saturn terms run to high multiples
+        }
+        for k1 := 0; k1 <= kmax; k1++ {
+            for k2 := -7; k2 <= 7; k2++ {
+                if k2 != 0 && (k1 > 0 || k2 > 0) {
+                    mixed = append(mixed, term{k1, q, k2})
+                }
+            }
+        }
+    }
+    order := func(a term) int { return a.self + max(a.mult, -a.mult) }
+    sort.SliceStable(mixed, func(i, j int) bool { return order(mixed[i]) < order(mixed[j]) })
+    all = append(all, mixed...)
+
+    var out []term
+    var freqs []float64
+    for _, a := range all {
+        f := float64(a.self) * motion(planet)
+        if a.body != 0 {
+            f += float64(a.mult) * motion(a.body)
+        }
+        f = math.Abs(f)
+        if f < 240 {
+            continue
+        }
+        clash := false
+        for _, g := range freqs {
+            clash = clash || math.Abs(f-g) < 90
+        }
+        if !clash {
+            freqs = append(freqs, f)
+            out = append(out, a)
+        }
+    }
+    return out
+}
+
+// chebyshev fills row with T0..Tn-1 at x.
+func chebyshev(row []float64, x float64) {
+    row[0] = 1
+    if len(row) > 1 {
+        row[1] = x
+    }
+    for k := 2; k < len(row); k++ {
+        row[k] = 2*x*row[k-1] - row[k-2]
+    }
+}
+
+// sample is one comparison: time and the longitude, latitude and distance
+// residuals.
+type sample struct {
+    t float64
+    d [3]float64
+}
+
+// design fills one row of the least-squares system: the polynomial, then for
+// each term sin and cos times T0..T(modDegree).
+func design(row []float64, planet int, terms []term, t float64) {
+    x := (2*t - fitStart - fitEnd) / (fitEnd - fitStart)
+    chebyshev(row[:polyCoef], x)
+    var mod [modDegree + 1]float64
+    chebyshev(mod[:], x)
+    for j, a := range terms {
+        s, c := math.Sincos(a.arg(planet, t))
+        for k, m := range mod {
+            i := polyCoef + 2*(modDegree+1)*j + 2*k
+            row[i], row[i+1] = m*s, m*c
+        }
+    }
+}
+
+// solve fits the residuals to the terms with a light ridge on the periodic
+// amplitudes, and returns the coefficients and the worst remaining residual
+// of each quantity.
+func solve(planet int, terms []term, samples []sample) (coef [3][]float64, worst [3]float64) {
+    n := polyCoef + 2*(modDegree+1)*len(terms)
+    ata := make([][]float64, n)
+    for i := range ata {
+        ata[i] = make([]float64, n)
+    }
+    var atb [3][]float64
+    for q := range atb {
+        atb[q] = make([]float64, n)
+    }
+    row := make([]float64, n)
+    for _, s := range samples {
+        design(row, planet, terms, s.t)
+        for r := 0; r < n; r++ {
+            for q := range atb {
+                atb[q][r] += row[r] * s.d[q]
+            }
+            for c := r; c < n; c++ {
+                ata[r][c] += row[r] * row[c]
+            }
+        }
+    }
+    for r := 0; r < n; r++ {
+        for c := 0; c < r; c++ {
+            ata[r][c] = ata[c][r]
+        }
+        if r >= polyCoef {
+            ata[r][r] += 1e-3 * float64(len(samples))
+        }
+    }
+    for q := range coef {
+        coef[q] = gauss(ata, atb[q])
+    }
+    for _, s := range samples {
+        for q, r := range residuals(row, planet, terms, coef, s) {
+            worst[q] = math.Max(worst[q], math.Abs(r))
+        }
+    }
+    return coef, worst
+}
+
+// residuals returns what the fit leaves of a sample.
+func residuals(row []float64, planet int, terms []term, coef [3][]float64, s sample) [3]float64 {
+    design(row, planet, terms, s.t)
+    r := s.d
+    for q := range r {
+        for i, v := range row {
+            r[q] -= v * coef[q][i]
+        }
+    }
+    return r
+}
+
+// gauss solves a x = b by elimination with partial pivoting, leaving a and b
+// untouched.
+func gauss(a [][]float64, b []float64) []float64 {
+    n := len(b)
+    m := make([][]float64, n)
+    for i := range m {
+        m[i] = append(append([]float64(nil), a[i]...), b[i])
+    }
+    for i := 0; i < n; i++ {
+        p := i
+        for r := i + 1; r < n; r++ {
+            if math.Abs(m[r][i]) > math.Abs(m[p][i]) {
+                p = r
+            }
+        }
+        m[i], m[p] = m[p], m[i]
+        for r := i + 1; r < n; r++ {
+            f := m[r][i] / m[i][i]
+            for c := i; c <= n; c++ {
+                m[r][c] -= f * m[i][c]
+            }
+        }
+    }
+    x := make([]float64, n)
+    for i := n - 1; i >= 0; i-- {
+        s := m[i][n]
+        for c := i + 1; c < n; c++ {
+            s -= m[i][c] * x[c]
+        }
+        x[i] = s / m[i][i]
+    }
+    return x
+}
+
+// fitPlanet picks terms greedily, each time adding the candidate that best
+// matches what is left, until the residuals meet the targets. The worst
+// residuals come back in arcseconds as seen from the Earth.
+func fitPlanet(planet int, start, end float64) (terms []term, coef [3][]float64, worst [3]float64) {
+    step := 2.3
+    if planet >= 5 {
+        step = 7.1
+    }
+    var samples []sample
+    for jd := start; jd < end; jd += step {
+        t := centuries(jd)
+        l0, b0, r0 := kepler(planet, t)
+        l1, b1, r1 := geometric(planet, jd)
+        samples = append(samples, sample{t, [3]float64{math.Remainder(l1-l0, 360) * 3600, (b1 - b0) * 3600, (r1 - r0) * 1e6}})
+    }
+    // Arcseconds per unit of each quantity at the planet's closest approach
+    scale := [3]float64{1, 1, 206265e-6 / nearest[planet]}
+    pool := candidates(planet)
+    for {
+        coef, worst = solve(planet, terms, samples)
+        for q := range worst {
+            worst[q] *= scale[q]
+        }
+        done := worst[0] < targets[0] && worst[1] < targets[1] && worst[2] < targets[2]
+        if done || len(terms) == maxTerms || len(pool) == 0 {
+            return terms, coef, worst
+        }
+        // Project the residuals on each candidate's sine and cosine
+        proj := make([][3][2]float64, len(pool))
+        row := make([]float64, polyCoef+2*(modDegree+1)*len(terms))
+        for _, s := range samples {
+            r := residuals(row, planet, terms, coef, s)
+            for i, a := range pool {
+                sn, cs := math.Sincos(a.arg(planet, s.t))
+                for q := range r {
+                    proj[i][q][0] += r[q] * sn
+                    proj[i][q][1] += r[q] * cs
+                }
+            }
+        }
+        best, bestScore := 0, -1.0
+        for i, p := range proj {
+            // Each quantity is weighed against its target
+            var score float64
+            for q := range p {
+                w := scale[q] / targets[q]
+                score += w * w * (p[q][0]*p[q][0] + p[q][1]*p[q][1])
+            }
+            if score > bestScore {
+                best, bestScore = i, score
+            }
+        }
+        terms = append(terms, pool[best])
+        pool = append(pool[:best:best], pool[best+1:]...)
+    }
+}
+
+// fitAyanamsa fits an ayanamsa as a quadratic in centuries from J2000, UT,
+// plus a multiple of the nutation in longitude and, for modes tied to a
+// star's apparent position, its annual aberration: cosine and sine of the
+// Sun's mean longitude.
+func fitAyanamsa(mode int, start, end float64) (c [6]float64, worst float64) {
+    swe.SetSidMode(mode, 0, 0)
+    daya := make([]float64, 1)
+    nut := make([]float64, 6)
+    serr := make([]byte, 256)
+    var rows [][6]float64
+    var ys []float64
+    ref := 0.0
+    for jd := start; jd < end; jd += 37 {
+        if swe.GetAyanamsaExUt(jd, seflgSwieph, daya, serr) < 0 || swe.CalcUt(jd, seEclNut, 0, nut, serr) < 0 {
+            fmt.Fprintf(os.Stderr, "approxfit: %s\n", bytes.TrimRight(serr, "\x00"))
+            os.Exit(1)
+        }
+        if ys == nil {
+            ref = daya[0]
+        }
+        t := (jd - 2451545) / 36525
+        sun := (280.46646 + 36000.76983*t) * deg2rad
+        rows = append(rows, [6]float64{1, t, t * t, nut[2], math.Cos(sun), math.Sin(sun)})
+        ys = append(ys, ref+math.Remainder(daya[0]-ref, 360))
+    }
+    ata := make([][]float64, 6)
+    for i := range ata {
+        ata[i] = make([]float64, 6)
+    }
+    aty := make([]float64, 6)
+    for i, row := range rows {
+        for r := range row {
+            aty[r] += row[r] * ys[i]
+            for k := range row {
+                ata[r][k] += row[r] * row[k]
+            }
+        }
+    }
+    x := gauss(ata, aty)
+    for i, row := range rows {
+        var v float64
+        for k := range row {
+            v += x[k] * row[k]
+        }
+        worst = math.Max(worst, math.Abs(v-ys[i])*3600)
+    }
+    x[0] = normDeg(x[0])
+    return [6]float64(x), worst
+}
+
+// bodyName spells a body the way the server's constants do.
+func bodyName(body int) string {
+    for _, e := range elements {
+        if e.body == body {
+            return e.name
+        }
+    }
+    return fmt.Sprint(body)
+}
+
+func floats(v []float64) string {
+    var b bytes.Buffer
+    for i, x := range v {
+        if i > 0 {
+            b.WriteString(", ")
+        }
+        fmt.Fprintf(&b, "%.2f", x)
+    }
+    return b.String()
+}
+
+func main() {
+    ephe := flag.String("ephe", "./swisseph/ephe", "Swiss Ephemeris data directory")
+    out := flag.String("o", "approx_tables.go", "output file")
+    maxResidual := flag.Float64("ayanamsa-max", 60, "leave out ayanamsas whose fit is worse than this, arcseconds")
+    flag.Parse()
+    runtime.LockOSThread()                    // Swiss Ephemeris keeps its settings per thread
+    swe.SetEphePath(append([]byte(*ephe), 0)) // C string
+
+    start := julianDay(time.Date(1800, 1, 1, 0, 0, 0, 0, time.UTC))
+    end := julianDay(time.Date(2201, 1, 1, 0, 0, 0, 0, time.UTC))
+    fitStart, fitEnd = centuries(start), centuries(end)
+    for _, e := range elements {
+        elementsOf[e.body] = e.el
+    }
+
+    var b bytes.Buffer
+    b.WriteString("// Code generated by go run ./tools/approxfit; DO NOT EDIT.\n\npackage main\n\n")
+    b.WriteString("// keplerElements are JPL's approximate Keplerian elements (Standish), J2000\n")
+    b.WriteString("// ecliptic: a (AU), e, I, L, long. perihelion, long. node (degrees), each\n")
+    b.WriteString("// with its rate per century.\n")
+    b.WriteString("var keplerElements = map[int][12]float64{\n")
+    for _, e := range elements {
+        fmt.Fprintf(&b, "    %s: {", e.name)
+        for i, v := range e.el {
+            switch {
+            case i == 6:
+                b.WriteString(",\n        ")
+            case i > 0:
+                b.WriteString(", ")
+            }
+            fmt.Fprintf(&b, "%.8f", v)
+        }
+        b.WriteString("},\n")
+    }
+    b.WriteString("}\n\n")
+
+    fmt.Println("ayanamsas, worst residual after fitting:")
+    b.WriteString("// approxAyanamsas fit each Swiss Ephemeris ayanamsa over 1800-2200: a\n")
+    b.WriteString("// quadratic in centuries from J2000, a multiple of the nutation, and the\n")
+    b.WriteString("// cosine and sine of the Sun's mean longitude for a star's aberration.\n")
+    b.WriteString("var approxAyanamsas = map[int][6]float64{\n")
+    for mode := 0; mode <= 46; mode++ {
+        c, worst := fitAyanamsa(mode, start, end)
+        fmt.Printf("  %2d %8.2f\"\n", mode, worst)
+        if worst > *maxResidual {
+            continue
+        }
+        fmt.Fprintf(&b, "    %d: {%.7f, %.7f, %.7f, %.4f, %.7f, %.7f},\n", mode, c[0], c[1], c[2], c[3], c[4], c[5])
+    }
+    b.WriteString("}\n\n")
+
+    fmt.Println("planets, worst residual after fitting, as seen from the Earth at closest approach:")
+    b.WriteString("// keplerFits correct the Keplerian position between these TT\n")
+    b.WriteString("// centuries from J2000 (1800-2200).\n")
+    fmt.Fprintf(&b, "const keplerFitStart, keplerFitEnd = %.6f, %.6f\n\n", centuries(start), centuries(end))
+    b.WriteString("var keplerFits = map[int]keplerFit{\n")
+    for _, e := range elements {
+        terms, coef, worst := fitPlanet(e.body, start, end)
+        fmt.Printf("  %-10s %2d terms, longitude %5.1f\", latitude %4.1f\", distance %4.1f\"\n",
+            e.name, len(terms), worst[0], worst[1], worst[2])
+        fmt.Fprintf(&b, "    %s: {\n", e.name)
+        for q, name := range []string{"lon", "lat", "dist"} {
+            fmt.Fprintf(&b, "        %s: [%d]float64{%s},\n", name, polyCoef, floats(coef[q][:polyCoef]))
+        }
+        b.WriteString("        terms: []keplerTerm{\n")
+        w := 2 * (modDegree + 1)
+        for j, a := range terms {
+            i := polyCoef + w*j
+            fmt.Fprintf(&b, "            {%d, %s, %d, [%d]float64{%s}, [%d]float64{%s}, [%d]float64{%s}},\n",
+                a.self, bodyName(a.body), a.mult, w, floats(coef[0][i:i+w]), w, floats(coef[1][i:i+w]), w, floats(coef[2][i:i+w]))
+        }
+        b.WriteString("        },\n    },\n")
+    }
+    b.WriteString("}\n")
+
+    if err := os.WriteFile(*out, b.Bytes(), 0o644); err != nil {
+        fmt.Fprintf(os.Stderr, "approxfit: %v\n", err)
+        os.Exit(1)
+    }
+    fmt.Printf("%s: %d bytes\n", *out, b.Len())
+}
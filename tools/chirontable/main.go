@@ -0,0 +1,164 @@
+//go:build cgo
+
+// Command chirontable fits Chebyshev polynomials to Swiss Ephemeris positions
+// of Chiron and writes the table embedded by the server (chiron_table.bin),
+// then checks the table against the live ephemeris and prints the worst error.
+//
+//	go run ./tools/chirontable -ephe ./swisseph/ephe -o chiron_table.bin
+package main
+
+import (
+    "bytes"
+    "encoding/binary"
+    "flag"
+    "fmt"
+    "math"
+    "os"
+    "sort"
+    "time"
+
+    swe "github.com/mshafiee/swephgo"
+)
+
+const (
+    seChiron    = 15
+    seflgSwieph = 2
+    seflgSpeed  = 256
+)
+
+// Layout (little endian): magic "CHEB", version uint32, start JD float64,
+// segment days float64, segments uint32, coefficients per series uint32, then
+// for each segment the longitude, latitude and distance series as float32.
+const magic = "CHEB"
+
+func julianDay(t time.Time) float64 {
+    return float64(t.Unix())/86400 + 2440587.5
+}
+
+func chiron(jd float64) (lon, lat, dist, speed float64) {
+    xx := make([]float64, 6)
+    serr := make([]byte, 256)
+    if swe.CalcUt(jd, seChiron, seflgSwieph|seflgSpeed, xx, serr) < 0 {
+        fmt.Fprintf(os.Stderr, "chirontable: %s\n", bytes.TrimRight(serr, "\x00"))
+        os.Exit(1)
+    }
+    return xx[0], xx[1], xx[2], xx[3]
+}
+
+// fit returns n Chebyshev coefficients for f over [-1, 1].
+func fit(f func(x float64) float64, n int) []float64 {
+    vals := make([]float64, n)
+    for k := range vals {
+        vals[k] = f(math.Cos(math.Pi * (float64(k) + 0.5) / float64(n)))
+    }
+    c := make([]float64, n)
+    for j := range c {
+        var s float64
+        for k, v := range vals {
+            s += v * math.Cos(math.Pi*float64(j)*(float64(k)+0.5)/float64(n))
+        }
+        c[j] = 2 * s / float64(n)
+    }
+    c[0] /= 2
+    return c
+}
+
+// eval returns the series and its derivative with respect to x.
+func eval(c []float32, x float64) (v, d float64) {
+    var t0, t1 = 1.0, x
+    var u0, u1 = 0.0, 1.0 // dT/dx
+    v = float64(c[0]) + float64(c[1])*x
+    d = float64(c[1])
+    for j := 2; j < len(c); j++ {
+        t2 := 2*x*t1 - t0
+        u2 := 2*t1 + 2*x*u1 - u0
+        v += float64(c[j]) * t2
+        d += float64(c[j]) * u2
+        t0, t1, u0, u1 = t1, t2, u1, u2
+    }
+    return v, d
+}
+
+func main() {
+    ephe := flag.String("ephe", "./swisseph/ephe", "Swiss Ephemeris data directory")
+    out := flag.String("o", "chiron_table.bin", "output file")
+    from := flag.Int("from", 1800, "first year covered")
+    to := flag.Int("to", 2200, "last year covered")
+    segDays := flag.Float64("segment", 32, "days per segment")
+    ncoef := flag.Int("coef", 10, "coefficients per series")
+    checks := flag.Int("checks", 16, "verification points per segment")
+    flag.Parse()
+    swe.SetEphePath(append([]byte(*ephe), 0)) // C string
+
+    start := julianDay(time.Date(*from, 1, 1, 0, 0, 0, 0, time.UTC))
+    end := julianDay(time.Date(*to+1, 1, 1, 0, 0, 0, 0, time.UTC))
+    nseg := int(math.Ceil((end - start) / *segDays))
+
+    var buf bytes.Buffer
+    buf.WriteString(magic)
+    binary.Write(&buf, binary.LittleEndian, uint32(1))
+    binary.Write(&buf, binary.LittleEndian, start)
+    binary.Write(&buf, binary.LittleEndian, *segDays)
+    binary.Write(&buf, binary.LittleEndian, uint32(nseg))
+    binary.Write(&buf, binary.LittleEndian, uint32(*ncoef))
+
+    var errLon, errLat, errDist, errSpeed []float64
+    for s := 0; s < nseg; s++ {
+        a := start + float64(s)**segDays
+        jdAt := func(x float64) float64 { return a + (x+1)/2**segDays }
+        ref, _, _, _ := chiron(a + *segDays/2)
+        // Longitude is unwrapped around the segment midpoint so it stays continuous
+        series := [3][]float64{
+            fit(func(x float64) float64 {
+                lon, _, _, _ := chiron(jdAt(x))
+                return ref + math.Remainder(lon-ref, 360)
+            }, *ncoef),
+            fit(func(x float64) float64 { _, lat, _, _ := chiron(jdAt(x)); return lat }, *ncoef),
+            fit(func(x float64) float64 { _, _, dist, _ := chiron(jdAt(x)); return dist }, *ncoef),
+        }
+        var stored [3][]float32
+        for i, c := range series {
+            stored[i] = make([]float32, len(c))
+            for j, v := range c {
+                stored[i][j] = float32(v)
+            }
+            binary.Write(&buf, binary.LittleEndian, stored[i])
+        }
+
+        for k := 0; k <= *checks; k++ {
+            x := -1 + 2*float64(k)/float64(*checks)
+            lon, lat, dist, speed := chiron(jdAt(x))
+            l, dl := eval(stored[0], x)
+            b, _ := eval(stored[1], x)
+            r, _ := eval(stored[2], x)
+            errLon = append(errLon, math.Abs(math.Remainder(l-lon, 360))*3600)
+            errLat = append(errLat, math.Abs(b-lat)*3600)
+            errDist = append(errDist, math.Abs(r-dist))
+            errSpeed = append(errSpeed, math.Abs(dl*2 / *segDays - speed)*3600)
+        }
+    }
+
+    if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+        fmt.Fprintf(os.Stderr, "chirontable: %v\n", err)
+        os.Exit(1)
+    }
+    fmt.Printf("%s: %d-%d, %d segments of %g days, %d coefficients, %d bytes\n",
+        *out, *from, *to, nseg, *segDays, *ncoef, buf.Len())
+    fmt.Printf("error against Swiss Ephemeris, %d points per segment:\n", *checks+1)
+    fmt.Printf("  %-10s %12s %12s %12s\n", "", "median", "99.9%", "max")
+    report("longitude", "\"", errLon)
+    report("latitude", "\"", errLat)
+    report("distance", " AU", errDist)
+    report("speed", "\"/d", errSpeed)
+}
+
+// report prints quantiles of the absolute errors. The maximum is dominated by
+// a few small steps in Swiss Ephemeris's own Chiron series, which the smooth
+// fit does not follow.
+func report(name, unit string, errs []float64) {
+    sort.Float64s(errs)
+    q := func(p float64) string {
+        return fmt.Sprintf("%.3g%s", errs[int(p*float64(len(errs)-1))], unit)
+    }
+    fmt.Printf("  %-10s %12s %12s %12s\n", name, q(0.5), q(0.999), q(1))
+}
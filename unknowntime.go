@@ -0,0 +1,194 @@
+package main
+
+import (
+    "math"
+    "net/http"
+    "time"
+)
+
+// ===== Unknown birth time =====
+
+// Houses and signs are resolved to the minute, which is finer than birth
+// times are usually known to anyway. The day is sampled coarsely and each
+// change bisected; a house lasts far longer than the coarse step.
+const (
+    unknownTimeStep   = time.Minute
+    unknownTimeCoarse = 10 * time.Minute
+)
+
+type SignChange struct {
+    At   string `json:"at"` // local time, RFC 3339
+    Sign string `json:"sign"`
+}
+
+// HouseWindow is a stretch of the birth day during which Chiron falls in one house.
+type HouseWindow struct {
+    House            int    `json:"house"`
+    From             string `json:"from"` // local time, RFC 3339
+    To               string `json:"to"`
+    Sign             string `json:"sign"`
+    TraditionalWound string `json:"traditional_wound"`
+    LHPStrength      string `json:"lhp_strength"`
+}
+
+type UnknownTimeReading struct {
+    UnknownTime    bool          `json:"unknown_time"`
+    Sign           string        `json:"sign"`         // at local noon
+    Degree         float64       `json:"degree"`       // at local noon
+    DegreeRange    [2]float64    `json:"degree_range"` // start and end of the day, within the sign
+    SignChanges    []SignChange  `json:"sign_changes,omitempty"`
+    HousesReliable bool          `json:"houses_reliable"` // always false: pick a window with the client
+    Houses         []HouseWindow `json:"houses,omitempty"`
+    Note           string        `json:"note"`
+    Zodiac         string        `json:"zodiac"`
+    Ayanamsa       string        `json:"ayanamsa,omitempty"`
+    Center         string        `json:"center"`
+}
+
+type daySample struct {
+    at    time.Time
+    lon   float64
+    house int // 0 when houses can't be computed
+}
+
+// same reports whether Chiron is in the same sign and house at both samples.
+func (s daySample) same(o daySample) bool {
+    return s.house == o.house && signFromLongitude(s.lon) == signFromLongitude(o.lon)
+}
+
+// sampleAt computes Chiron and its house at t, holding the ephemeris for
+// that sample only.
+func sampleAt(t time.Time, lat, lon float64, hsys byte, opts calcOptions) (daySample, error) {
+    jd := julianDay(t.UTC())
+    s := daySample{at: t}
+    var err error
+    withEphemeris(opts, func() {
+        if s.lon, _, err = chironSpeedAt(jd, opts.flags()); err != nil {
+            return
+        }
+        cusps := make([]float64, 13)
+        ascmc := make([]float64, 10)
+        if eph.HousesEx(jd, opts.houseFlags(), lat, lon, int(hsys), cusps, ascmc) == nil {
+            s.house = houseFromCusps(cusps, s.lon)
+        }
+    })
+    if err != nil {
+        ephemerisErrors.inc("chiron")
+    }
+    return s, err
+}
+
+// sampleBirthDay returns Chiron and its house at start and at each minute of
+// [start, end) where the sign or house changes, plus the day's last minute.
+func sampleBirthDay(start, end time.Time, lat, lon float64, hsys byte, opts calcOptions) (changes []daySample, last daySample, err error) {
+    at := func(t time.Time) (daySample, error) { return sampleAt(t, lat, lon, hsys, opts) }
+    // refine appends the first minute of each change between a and b
+    var refine func(a, b daySample) error
+    refine = func(a, b daySample) error {
+        if b.at.Sub(a.at) <= unknownTimeStep {
+            changes = append(changes, b)
+            return nil
+        }
+        m, err := at(a.at.Add((b.at.Sub(a.at) / 2).Truncate(unknownTimeStep)))
+        if err != nil {
+            return err
+        }
+        if !a.same(m) {
+            if err := refine(a, m); err != nil {
+                return err
+            }
+        }
+        if !m.same(b) {
+            return refine(m, b)
+        }
+        return nil
+    }
+
+    final := end.Add(-unknownTimeStep)
+    prev, err := at(start)
+    if err != nil {
+        return nil, daySample{}, err
+    }
+    changes = append(changes, prev)
+    for t := start; t.Before(final); {
+        t = t.Add(unknownTimeCoarse)
+        if t.After(final) {
+            t = final
+        }
+        cur, err := at(t)
+        if err != nil {
+            return nil, daySample{}, err
+        }
+        if !prev.same(cur) {
+            if err := refine(prev, cur); err != nil {
+                return nil, daySample{}, err
+            }
+        }
+        prev = cur
+    }
+    return changes, prev, nil
+}
+
+// unknownTimeHandler answers a chironHandler request with unknown_time set.
+func unknownTimeHandler(w http.ResponseWriter, r *http.Request, req BirthData, loc *time.Location, opts calcOptions) {
+    start := time.Date(req.Year, time.Month(req.Month), req.Day, 0, 0, 0, 0, loc)
+    end := start.AddDate(0, 0, 1) // not always 24h across a DST change
+
+    ephStart := time.Now()
+    hsys := houseSystems[appConfig.Ephemeris.HouseSystem]
+    samples, last, err := sampleBirthDay(start, end, req.Lat, req.Lon, hsys, opts)
+    var noon daySample
+    if err == nil {
+        noon, err = sampleAt(time.Date(req.Year, time.Month(req.Month), req.Day, 12, 0, 0, 0, loc), req.Lat, req.Lon, hsys, opts)
+    }
+    observeEphemeris(r.Context(), "unknown_time", ephStart)
+    if err != nil {
+        http.Error(w, "ephemeris unavailable", http.StatusInternalServerError)
+        return
+    }
+
+    first := samples[0]
+    sign := signFromLongitude(noon.lon)
+
+    resp := UnknownTimeReading{
+        UnknownTime: true,
+        Sign:        sign,
+        Degree:      math.Round(math.Mod(noon.lon, 30)*100) / 100,
+        DegreeRange: [2]float64{math.Round(math.Mod(first.lon, 30)*100) / 100, math.Round(math.Mod(last.lon, 30)*100) / 100},
+        Zodiac:      opts.Zodiac,
+        Ayanamsa:    opts.Ayanamsa,
+        Center:      opts.center(),
+        Note:        "Birth time unknown: sign and degree are given for local noon, and the house depends on the time of birth.",
+    }
+
+    housesKnown := true
+    for i, s := range samples {
+        if s.house == 0 {
+            housesKnown = false
+        }
+        if i > 0 && signFromLongitude(s.lon) != signFromLongitude(samples[i-1].lon) {
+            resp.SignChanges = append(resp.SignChanges, SignChange{At: s.at.Format(time.RFC3339), Sign: signFromLongitude(s.lon)})
+        }
+    }
+    if len(resp.SignChanges) > 0 {
+        resp.Note += " Chiron changes sign during the day, so the sign also depends on the time of birth."
+    }
+
+    if !housesKnown {
+        resp.Note += " Houses can't be determined at this latitude in the configured house system."
+    } else {
+        // Each sample opens a window that runs until the next one
+        for i, s := range samples {
+            to := end
+            if i+1 < len(samples) {
+                to = samples[i+1].at
+            }
+            sign := signFromLongitude(s.lon)
+            wound, strength := getInterpretation(sign, s.house)
+            resp.Houses = append(resp.Houses, HouseWindow{House: s.house, From: s.at.Format(time.RFC3339),
+                To: to.Format(time.RFC3339), Sign: sign, TraditionalWound: wound, LHPStrength: strength})
+        }
+    }
+
+    writeJSON(w, http.StatusOK, resp)
+}
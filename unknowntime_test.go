@@ -0,0 +1,58 @@
+package main
+
+import (
+    "testing"
+    "time"
+)
+
+// The coarse scan with bisection must find the same changes as sampling
+// every minute.
+func TestSampleBirthDayMatchesMinuteScan(t *testing.T) {
+    useFake(t)
+    loc, err := time.LoadLocation("Europe/London")
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, c := range []struct {
+        day      time.Time
+        lat, lon float64
+        hsys     byte
+    }{
+        {time.Date(1990, 6, 15, 0, 0, 0, 0, loc), 51.5, -0.12, 'E'},
+        {time.Date(1990, 6, 15, 0, 0, 0, 0, loc), 51.5, -0.12, 'W'},
+        {time.Date(2021, 3, 28, 0, 0, 0, 0, loc), 66, 25, 'E'}, // 23-hour day
+    } {
+        end := c.day.AddDate(0, 0, 1)
+        changes, last, err := sampleBirthDay(c.day, end, c.lat, c.lon, c.hsys, tropical)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        var want []daySample
+        var prev daySample
+        for at := c.day; at.Before(end); at = at.Add(unknownTimeStep) {
+            s, err := sampleAt(at, c.lat, c.lon, c.hsys, tropical)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if len(want) == 0 || !prev.same(s) {
+                want = append(want, s)
+            }
+            prev = s
+        }
+
+        if len(changes) != len(want) {
+            t.Errorf("%s %c: %d changes, want %d", c.day.Format("2006-01-02"), c.hsys, len(changes), len(want))
+            continue
+        }
+        for i := range want {
+            if !changes[i].at.Equal(want[i].at) || !changes[i].same(want[i]) {
+                t.Errorf("%s %c: change %d at %s house %d, want %s house %d", c.day.Format("2006-01-02"), c.hsys,
+                    i, changes[i].at.Format(time.Kitchen), changes[i].house, want[i].at.Format(time.Kitchen), want[i].house)
+            }
+        }
+        if !last.at.Equal(prev.at) {
+            t.Errorf("last sample at %s, want %s", last.at, prev.at)
+        }
+    }
+}
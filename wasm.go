@@ -0,0 +1,150 @@
+//go:build js && wasm
+
+package main
+
+import (
+    "bytes"
+    "net/http"
+    "strings"
+    "syscall/js"
+    _ "time/tzdata" // browsers give Go no zoneinfo of their own
+)
+
+// ===== WebAssembly entry point =====
+//
+// Built with GOOS=js GOARCH=wasm, the reading pipeline runs in the browser
+// on the pure-Go ephemeris, so birth data never leaves the device. main
+// installs a global chironOracle object (see public/oracle.js) and stays
+// alive to serve its calls. Every function returns {error: "..."} instead
+// of throwing.
+
+func main() {
+    appConfig = defaultConfig()
+    setupLogging(logConfig{Level: "warn"})
+
+    js.Global().Set("chironOracle", js.ValueOf(map[string]interface{}{
+        "backend":         eph.Name(),
+        "reading":         js.FuncOf(jsReading),
+        "julianDay":       js.FuncOf(jsJulianDay),
+        "chironLongitude": js.FuncOf(jsChironLongitude),
+        "houseCusps":      js.FuncOf(jsHouseCusps),
+        "interpretation":  js.FuncOf(jsInterpretation),
+    }))
+    select {}
+}
+
+func jsError(msg string) interface{} {
+    return map[string]interface{}{"error": msg}
+}
+
+// bufferedResponse collects what a handler writes.
+type bufferedResponse struct {
+    header http.Header
+    status int
+    body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header         { return b.header }
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+func (b *bufferedResponse) WriteHeader(status int)      { b.status = status }
+
+// jsReading(birth) answers exactly as POST /api/chiron would for the same
+// body, by running chironHandler itself.
+func jsReading(this js.Value, args []js.Value) interface{} {
+    if len(args) != 1 {
+        return jsError("reading expects one birth data object")
+    }
+    body := args[0]
+    if body.Type() != js.TypeString {
+        body = js.Global().Get("JSON").Call("stringify", body)
+    }
+    r, err := http.NewRequest(http.MethodPost, "/api/chiron", strings.NewReader(body.String()))
+    if err != nil {
+        return jsError(err.Error())
+    }
+    r.Header.Set("Content-Type", "application/json")
+    w := &bufferedResponse{header: http.Header{}, status: http.StatusOK}
+    chironHandler(w, r)
+    if w.status != http.StatusOK {
+        return jsError(strings.TrimSpace(w.body.String()))
+    }
+    return js.Global().Get("JSON").Call("parse", w.body.String())
+}
+
+// jsJulianDay(year, month, day, hour) takes a UT Gregorian date.
+func jsJulianDay(this js.Value, args []js.Value) interface{} {
+    if len(args) != 4 {
+        return jsError("julianDay expects year, month, day and hour (UT)")
+    }
+    return eph.JulDay(args[0].Int(), args[1].Int(), args[2].Int(), args[3].Float())
+}
+
+// optionsArg reads the optional {zodiac, ayanamsa} argument.
+func optionsArg(args []js.Value, i int) (calcOptions, error) {
+    var req BirthData
+    if len(args) > i && args[i].Type() == js.TypeObject {
+        if z := args[i].Get("zodiac"); z.Type() == js.TypeString {
+            req.Zodiac = z.String()
+        }
+        if a := args[i].Get("ayanamsa"); a.Type() == js.TypeString {
+            req.Ayanamsa = a.String()
+        }
+    }
+    return resolveCalcOptions(req)
+}
+
+// jsChironLongitude(jd, options) returns Chiron's ecliptic longitude.
+func jsChironLongitude(this js.Value, args []js.Value) interface{} {
+    if len(args) < 1 {
+        return jsError("chironLongitude expects a Julian Day")
+    }
+    opts, err := optionsArg(args, 1)
+    if err != nil {
+        return jsError(err.Error())
+    }
+    lon, err := computeChironLongitude(args[0].Float(), opts)
+    if err != nil {
+        return jsError(err.Error())
+    }
+    return lon
+}
+
+// jsHouseCusps(jd, lat, lon, system, options) returns the twelve cusps.
+func jsHouseCusps(this js.Value, args []js.Value) interface{} {
+    if len(args) < 3 {
+        return jsError("houseCusps expects a Julian Day, latitude and longitude")
+    }
+    system := appConfig.Ephemeris.HouseSystem
+    if len(args) > 3 && args[3].Type() == js.TypeString {
+        system = args[3].String()
+    }
+    hsys, ok := houseSystems[system]
+    if !ok {
+        return jsError("unknown house system " + system)
+    }
+    if err := checkHouseSystem(eph, system); err != nil {
+        return jsError(err.Error())
+    }
+    opts, err := optionsArg(args, 4)
+    if err != nil {
+        return jsError(err.Error())
+    }
+    cusps, err := computeHouseCusps(args[0].Float(), args[1].Float(), args[2].Float(), hsys, opts)
+    if err != nil {
+        return jsError(err.Error())
+    }
+    out := make([]interface{}, 12)
+    for i := range out {
+        out[i] = cusps[i+1]
+    }
+    return out
+}
+
+// jsInterpretation(sign, house) returns the reading texts.
+func jsInterpretation(this js.Value, args []js.Value) interface{} {
+    if len(args) != 2 {
+        return jsError("interpretation expects a sign and a house")
+    }
+    wound, strength := getInterpretation(args[0].String(), args[1].Int())
+    return map[string]interface{}{"traditional_wound": wound, "lhp_strength": strength}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "net/http"
+    "runtime"
+    "sort"
+)
+
+// ===== Zodiac / ayanamsa =====
+
+// ayanamsas maps request names to Swiss Ephemeris SE_SIDM_* modes.
+var ayanamsas = map[string]int{
+    "fagan_bradley":        0,
+    "lahiri":               1,
+    "deluce":               2,
+    "raman":                3,
+    "ushashashi":           4,
+    "krishnamurti":         5,
+    "djwhal_khul":          6,
+    "yukteshwar":           7,
+    "jn_bhasin":            8,
+    "babyl_kugler1":        9,
+    "babyl_kugler2":        10,
+    "babyl_kugler3":        11,
+    "babyl_huber":          12,
+    "babyl_etpsc":          13,
+    "aldebaran_15tau":      14,
+    "hipparchos":           15,
+    "sassanian":            16,
+    "galcent_0sag":         17,
+    "j2000":                18,
+    "j1900":                19,
+    "b1950":                20,
+    "suryasiddhanta":       21,
+    "suryasiddhanta_msun":  22,
+    "aryabhata":            23,
+    "aryabhata_msun":       24,
+    "ss_revati":            25,
+    "ss_citra":             26,
+    "true_citra":           27,
+    "true_revati":          28,
+    "true_pushya":          29,
+    "galcent_rgilbrand":    30,
+    "galequ_iau1958":       31,
+    "galequ_true":          32,
+    "galequ_mula":          33,
+    "galalign_mardyks":     34,
+    "true_mula":            35,
+    "galcent_mula_wilhelm": 36,
+    "aryabhata_522":        37,
+    "babyl_britton":        38,
+    "true_sheoran":         39,
+    "galcent_cochrane":     40,
+    "galequ_fiorenza":      41,
+    "valens_moon":          42,
+    "lahiri_1940":          43,
+    "lahiri_vp285":         44,
+    "krishnamurti_vp291":   45,
+    "lahiri_icrc":          46,
+}
+
+// calcOptions selects how positions are computed for one reading.
+type calcOptions struct {
+    Zodiac   string // tropical or sidereal
+    Ayanamsa string // key of ayanamsas, only used when sidereal
+
+    Heliocentric bool
+    Topocentric  bool
+    Lat, Lon     float64 // observer, for topocentric positions
+    Altitude     float64 // metres
+}
+
+func (o calcOptions) center() string {
+    switch {
+    case o.Heliocentric:
+        return "heliocentric"
+    case o.Topocentric:
+        return "topocentric"
+    }
+    return "geocentric"
+}
+
+func (o calcOptions) sidereal() bool {
+    return o.Zodiac == "sidereal"
+}
+
+// flags returns the SEFLG_* bits for swe.CalcUt.
+func (o calcOptions) flags() int {
+    f := SEFLG_SWIEPH | SEFLG_SPEED
+    if o.sidereal() {
+        f |= SEFLG_SIDEREAL
+    }
+    if o.Heliocentric {
+        f |= SEFLG_HELCTR
+    }
+    if o.Topocentric {
+        f |= SEFLG_TOPOCTR
+    }
+    return f
+}
+
+// houseFlags returns the SEFLG_* bits for swe.HousesEx.
+func (o calcOptions) houseFlags() int {
+    if o.sidereal() {
+        return SEFLG_SIDEREAL
+    }
+    return 0
+}
+
+var tropical = calcOptions{Zodiac: "tropical"}
+
+// resolveCalcOptions fills unset request options from the configured defaults.
+func resolveCalcOptions(req BirthData) (calcOptions, error) {
+    o := calcOptions{
+        Zodiac:       req.Zodiac,
+        Ayanamsa:     req.Ayanamsa,
+        Heliocentric: req.Heliocentric,
+        Topocentric:  req.Topocentric,
+        Lat:          req.Lat,
+        Lon:          req.Lon,
+        Altitude:     req.Altitude,
+    }
+    if o.Zodiac == "" {
+        o.Zodiac = appConfig.Ephemeris.Zodiac
+    }
+    if o.Ayanamsa == "" {
+        o.Ayanamsa = appConfig.Ephemeris.Ayanamsa
+    }
+    if !zodiacs[o.Zodiac] {
+        return o, fmt.Errorf("unknown zodiac %q (tropical or sidereal)", o.Zodiac)
+    }
+    if _, ok := ayanamsas[o.Ayanamsa]; !ok && o.sidereal() {
+        return o, fmt.Errorf("unknown ayanamsa %q, see /api/ayanamsas", o.Ayanamsa)
+    }
+    if o.Heliocentric && o.Topocentric {
+        return o, errors.New("heliocentric and topocentric are mutually exclusive")
+    }
+    if o.Altitude < -500 || o.Altitude > 20000 {
+        return o, fmt.Errorf("altitude %.0f m out of range", o.Altitude)
+    }
+    if err := checkCalcOptions(eph, o); err != nil {
+        return o, err
+    }
+    if !o.sidereal() {
+        o.Ayanamsa = ""
+    }
+    return o, nil
+}
+
+// withEphemeris applies o's sidereal mode and observer and runs fn with them.
+// libswe keeps those settings per OS thread, so the goroutine is pinned to
+// its thread meanwhile; backends that share them between callers are locked
+// as well (see settingsLocker).
+func withEphemeris(o calcOptions, fn func()) {
+    runtime.LockOSThread()
+    defer runtime.UnlockOSThread()
+    defer lockSettings(eph, o.sidereal() || o.Topocentric)()
+
+    if o.sidereal() {
+        eph.SetSidMode(ayanamsas[o.Ayanamsa])
+    }
+    if o.Topocentric {
+        eph.SetTopo(o.Lon, o.Lat, o.Altitude)
+    }
+    fn()
+}
+
+// computeAyanamsa returns the ayanamsa in degrees at jd (UT), or 0 for tropical.
+func computeAyanamsa(jd float64, o calcOptions) (float64, error) {
+    if !o.sidereal() {
+        return 0, nil
+    }
+    var ayanamsa float64
+    var err error
+    withEphemeris(o, func() {
+        ayanamsa, err = eph.AyanamsaUt(jd)
+    })
+    if err != nil {
+        ephemerisErrors.inc("ayanamsa")
+        return 0, err
+    }
+    return ayanamsa, nil
+}
+
+func ayanamsasHandler(w http.ResponseWriter, r *http.Request) {
+    names := make([]string, 0, len(ayanamsas))
+    for name := range ayanamsas {
+        names = append(names, name)
+    }
+    sort.Slice(names, func(i, j int) bool { return ayanamsas[names[i]] < ayanamsas[names[j]] })
+    writeJSON(w, http.StatusOK, map[string]interface{}{
+        "default":   appConfig.Ephemeris.Ayanamsa,
+        "ayanamsas": names,
+    })
+}